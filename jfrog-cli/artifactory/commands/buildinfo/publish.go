@@ -1,12 +1,20 @@
 package buildinfo
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/cliutils"
 	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+	serviceutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/http"
+	"net/url"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -32,7 +40,16 @@ func Publish(buildName, buildNumber string, config *buildinfo.Configuration, art
 		buildInfo.Append(v)
 	}
 
-	if err = servicesManager.PublishBuildInfo(buildInfo); err != nil {
+	project, err := utils.ReadBuildProject(buildName, buildNumber)
+	if err != nil {
+		return err
+	}
+	if project != "" {
+		err = publishProjectBuildInfo(buildInfo, project, servicesManager.GetConfig())
+	} else {
+		err = servicesManager.PublishBuildInfo(buildInfo)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -42,6 +59,38 @@ func Publish(buildName, buildNumber string, config *buildinfo.Configuration, art
 	return nil
 }
 
+// Publishes the build info to the Artifactory project, the same way the build info is published by the services manager.
+// Projects-enabled Artifactory instances store the build info in the build info repository of the project.
+func publishProjectBuildInfo(buildInfo *buildinfo.BuildInfo, project string, serviceConfig artifactory.Config) error {
+	content, err := json.Marshal(buildInfo)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	if serviceConfig.IsDryRun() {
+		log.Output(clientutils.IndentJson(content))
+		return nil
+	}
+	artDetails := serviceConfig.GetArtDetails()
+	httpClientsDetails := artDetails.CreateHttpClientDetails()
+	serviceutils.SetContentType("application/vnd.org.jfrog.artifactory+json", &httpClientsDetails.Headers)
+	// The client is created from the service config, so that it trusts the certificates the services manager loads.
+	client, err := artifactory.CreateArtifactoryHttpClient(serviceConfig)
+	if err != nil {
+		return err
+	}
+	log.Info("Deploying build info to the", project, "project...")
+	resp, body, err := client.SendPut(artDetails.GetUrl()+"api/build?project="+url.QueryEscape(project), content, httpClientsDetails)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return errorutils.CheckError(errors.New("Artifactory response: " + resp.Status + "\n" + clientutils.IndentJson(body)))
+	}
+	log.Debug("Artifactory response:", resp.Status)
+	log.Info("Build info successfully deployed. Browse it in Artifactory under " + artDetails.GetUrl() + "webapp/builds/" + buildInfo.Name + "/" + buildInfo.Number)
+	return nil
+}
+
 func createBuildInfoFromPartials(buildName, buildNumber string, config *buildinfo.Configuration, artDetails *config.ArtifactoryDetails) (*buildinfo.BuildInfo, error) {
 	partials, err := utils.ReadPartialBuildInfoFiles(buildName, buildNumber)
 	if err != nil {
@@ -98,7 +147,9 @@ func extractBuildInfoData(partials buildinfo.Partials, includeFilter, excludeFil
 			}
 		case partial.Vcs != nil:
 			vcs = *partial.Vcs
-		case partial.Env != nil:
+		}
+		// Env may be saved together with the artifacts, for example the upload stats.
+		if partial.Env != nil {
 			envAfterIncludeFilter, e := includeFilter(partial.Env)
 			if errorutils.CheckError(e) != nil {
 				return partialModulesToModules(partialModules), env, vcs, e
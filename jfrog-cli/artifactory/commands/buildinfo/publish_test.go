@@ -1,6 +1,11 @@
 package buildinfo
 
 import (
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 )
@@ -61,3 +66,43 @@ func TestExcludePasswordsPattern(t *testing.T) {
 		t.Error("expeted:", expected, "got:", filteredKeys)
 	}
 }
+
+func TestExtractBuildInfoDataEnvWithArtifacts(t *testing.T) {
+	partials := buildinfo.Partials{{Artifacts: []buildinfo.Artifact{{Name: "a.txt", Checksum: &buildinfo.Checksum{Sha1: "sha1", Md5: "md5"}}}, Env: buildinfo.Env{"jfrog.upload.bytes": "10"}}}
+	modules, env, _, err := extractBuildInfoData(partials, createIncludeFilter("*"), createExcludeFilter("*password*"))
+	if err != nil {
+		t.Error(err)
+	}
+	if len(modules) != 1 || len(modules[0].Artifacts) != 1 {
+		t.Error("expected 1 module with 1 artifact, got:", modules)
+	}
+	if env["jfrog.upload.bytes"] != "10" {
+		t.Error("expected the env saved with the artifacts, got:", env)
+	}
+}
+
+func TestPublishProjectBuildInfo(t *testing.T) {
+	var publishedQuery string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.URL.Path == "/api/build" {
+			publishedQuery = r.URL.RawQuery
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	buildName, buildNumber := "publish-project", "1"
+	defer utils.RemoveBuildDir(buildName, buildNumber)
+	if err := utils.SaveBuildProject(buildName, buildNumber, "proj"); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.SaveBuildProject(buildName, buildNumber, "other"); err == nil {
+		t.Error("Expected an error when associating the build with a different project")
+	}
+
+	if err := Publish(buildName, buildNumber, &buildinfo.Configuration{}, &config.ArtifactoryDetails{Url: ts.URL + "/"}); err != nil {
+		t.Fatal(err)
+	}
+	if publishedQuery != "project=proj" {
+		t.Error("Expected the build info to be published to the proj project, got the query:", publishedQuery)
+	}
+}
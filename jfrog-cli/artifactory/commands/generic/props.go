@@ -39,22 +39,7 @@ func DeleteProps(spec *spec.SpecFiles, props string, threads int, artDetails *co
 }
 
 func createPropsServiceManager(threads int, artDetails *config.ArtifactoryDetails) (*artifactory.ArtifactoryServicesManager, error) {
-	certPath, err := utils.GetJfrogSecurityDir()
-	if err != nil {
-		return nil, err
-	}
-	artAuth, err := artDetails.CreateArtAuthConfig()
-	if err != nil {
-		return nil, err
-	}
-	serviceConfig, err := artifactory.NewConfigBuilder().
-		SetArtDetails(artAuth).
-		SetCertificatesPath(certPath).
-		SetLogger(log.Logger).
-		SetThreads(threads).
-		Build()
-
-	return artifactory.New(serviceConfig)
+	return utils.NewServicesManager(artDetails, utils.WithThreads(threads))
 }
 
 func searchItems(spec *spec.SpecFiles, servicesManager *artifactory.ArtifactoryServicesManager) (resultItems []clientutils.ResultItem) {
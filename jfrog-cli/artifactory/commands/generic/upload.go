@@ -3,120 +3,480 @@ package generic
 import (
 	"errors"
 	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
-	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/utils"
-	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/config"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/cliutils"
 	"github.com/jfrog/jfrog-client-go/artifactory"
-	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
 	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/crypto/openpgp"
+	"io"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 )
 
+// The build properties recording the upload stats, when build info is collected.
+const (
+	UploadBytesProp      = "jfrog.upload.bytes"
+	UploadDurationProp   = "jfrog.upload.durationMillis"
+	UploadThroughputProp = "jfrog.upload.throughputBytesPerSecond"
+)
+
+// The property attached to the uploaded artifacts with the time of the upload, when configuration.AddUploadTimestampProp is set.
+const UploadTimestampProp = "jfrog.upload.timestamp"
+
+// The property attached to the uploaded artifacts with the time they expire, when configuration.Expiry is set.
+// The property is also added to the build properties, when build info is collected.
+const ExpiryProp = "jfrog.cleanup.expiry"
+
+// The error modes of the upload, which control whether the upload goes on after a spec file entry fails.
+const (
+	// Upload all of the spec file entries, and report the failures at the end.
+	ErrorModeContinue = "continue"
+	// Stop the upload at the first spec file entry which fails. The files of the entry are uploaded in parallel,
+	// so the entry itself is uploaded completely.
+	ErrorModeFailFast = "fail-fast"
+)
+
+// The writer to which the detailed summary and the conflicts preview are printed. Replaced in tests.
+var reportWriter io.Writer = os.Stdout
+
 // Uploads the artifacts in the specified local path pattern to the specified target path.
-// Returns the total number of artifacts successfully uploaded.
+// Returns the total number of artifacts successfully uploaded. The artifacts which were skipped, since they already
+// exist at their target path with the same checksum or since the deploy condition was not met, are not included.
+// UploadWithResult reports them.
+// If any of the files failed to upload, the returned error is an UploadPartialError, or an AuthError wrapping it when
+// the files failed due to authentication or authorization. If no artifacts matched, it is a NoMatchError.
 func Upload(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) (successCount, failCount int, err error) {
+	_, successCount, failCount, _, err = uploadWithResult(uploadSpec, configuration)
+	return
+}
 
-	// Create Service Manager:
-	certPath, err := utils.GetJfrogSecurityDir()
+// Same as Upload, but also returns the details of each of the artifacts which were successfully uploaded.
+// If configuration.BuildFlush is set, the details of the artifacts released once they are saved to the build info
+// are not returned.
+// If configuration.SummaryOutput is set, the details are also written to that file as JSON.
+// If configuration.DetailedSummary is set, a table of the details and of the files which failed to upload is printed.
+// If configuration.SummaryTemplate is set, the summary is rendered by the template and printed.
+// The skipped artifacts are counted as successfully uploaded, and the results of the artifacts which already existed
+// with the same checksum are marked as skipped.
+func UploadWithResult(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) (results []UploadResult, successCount, failCount int, err error) {
+	results, successCount, failCount, skippedCount, err := uploadWithResult(uploadSpec, configuration)
+	successCount += skippedCount
+	return
+}
+
+func uploadWithResult(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) (results []UploadResult, successCount, failCount, skippedCount int, err error) {
+	startTime := time.Now()
+	var summaryTemplate *template.Template
+	if configuration.SummaryTemplate != "" {
+		if summaryTemplate, err = parseSummaryTemplate(configuration.SummaryTemplate); err != nil {
+			return
+		}
+	}
+	// The checksum cache is also used for the checksums of the results, so it is kept until the results are created.
+	if configuration.ChecksumCacheDir != "" {
+		cache, cacheErr := loadChecksumCache(configuration.ChecksumCacheDir)
+		if cacheErr != nil {
+			err = cacheErr
+			return
+		}
+		setActiveChecksumCache(cache)
+		defer setActiveChecksumCache(nil)
+	}
+	filesResult, err := uploadFiles(uploadSpec, configuration)
+	successCount, failCount, skippedCount, failures := filesResult.successCount, filesResult.failCount, filesResult.skippedCount, filesResult.failures
+	results = convertFileInfoToUploadResults(filesResult.filesInfo, &filesResult, configuration.ArtDetails.Url, isCalcChecksums(configuration))
+	if cache := getActiveChecksumCache(); cache != nil {
+		// Failing to save the cache only makes the next upload recalculate the checksums.
+		if saveErr := cache.save(); saveErr != nil {
+			log.Warn("Failed saving the checksum cache:", saveErr.Error())
+		}
+	}
+	deduplication := getUploadDeduplication(results)
+	if deduplication.ChecksumDeployed > 0 {
+		log.Info("Deployed", strconv.Itoa(deduplication.ChecksumDeployed), "artifacts by checksum and fully uploaded", strconv.Itoa(deduplication.Uploaded), "artifacts, saving the transfer of", strconv.FormatInt(deduplication.BytesSaved, 10), "bytes.")
+	}
+	if configuration.DetailedSummary {
+		if summaryErr := writeDetailedSummary(reportWriter, results, failures); err == nil {
+			err = summaryErr
+		}
+	}
+	if configuration.SummaryOutput != "" {
+		summaryErr := writeUploadSummary(configuration.SummaryOutput, results, deduplication, successCount, failCount, err)
+		if err == nil {
+			err = summaryErr
+		}
+	}
+	if summaryTemplate != nil {
+		data := newUploadSummaryTemplateData(results, failures, successCount+skippedCount, failCount, skippedCount, time.Since(startTime), err)
+		if summaryErr := writeTemplateSummary(reportWriter, summaryTemplate, data); err == nil {
+			err = summaryErr
+		}
+	}
+	// The local files are deleted last, since the results and the summaries include their details.
+	if configuration.DeleteOnSuccess && !configuration.DryRun {
+		deleteUploadedSources(uploadSpec, configuration, results, failures)
+	}
+	return
+}
+
+// Returns true if the files which failed to upload are collected, since they are reported by the summary or
+// must not be deleted.
+func isFailuresCollected(configuration *UploadConfiguration) bool {
+	return configuration.DetailedSummary || configuration.SummaryTemplate != "" || configuration.DeleteOnSuccess
+}
+
+// The outcome of the upload of the files of all of the spec file entries.
+type uploadFilesResult struct {
+	filesInfo []clientutils.FileInfo
+	// The paths in which Artifactory stored the uploaded files, keyed by their target URL paths.
+	resolvedPaths map[string]string
+	// The target URL paths of the files, which were deployed by checksum.
+	checksumDeployed map[string]bool
+	// The target URL paths of the files, whose upload was skipped since they already existed with the same checksum.
+	skipped map[string]bool
+	// The files which failed to upload. Collected only when they are reported by the summary or must not be deleted.
+	failures     []UploadResult
+	successCount int
+	failCount    int
+	skippedCount int
+}
+
+func uploadFiles(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) (result uploadFilesResult, err error) {
+	// The props and the targets are added to a copy of the spec, so that a spec reused by the caller is unchanged.
+	uploadSpec = copySpecFiles(uploadSpec)
+	startTime := time.Now()
+	if configuration.TargetTime.IsZero() {
+		configuration.TargetTime = startTime
+	}
+	if err = validateUploadConfiguration(uploadSpec, configuration); err != nil {
+		return
+	}
+	var signer *openpgp.Entity
+	if configuration.SignArtifacts {
+		if signer, err = readSigningKey(configuration.SigningKeyPath, configuration.SigningKeyPassphrase); err != nil {
+			return
+		}
+	}
+	sidecarTemplate := ""
+	if configuration.SidecarTemplate != "" {
+		if sidecarTemplate, err = readSidecarTemplate(configuration.SidecarTemplate); err != nil {
+			return
+		}
+	}
+
+	// Upload Props:
+	expiry, err := addUploadProps(uploadSpec, configuration, startTime)
 	if err != nil {
-		return 0, 0, err
+		return
 	}
-	minChecksumDeploySize, err := getMinChecksumDeploySize()
+
+	// Planned Uploads:
+	// The files are resolved once, and the count, the total size, the dry run output and the input of the pre-upload
+	// hook are all derived from the planned uploads. The entries which fail to resolve are reported by the upload itself.
+	changedFilter, err := newChangedFilesFilter(configuration.ModifiedAfter, configuration.ChangedSince)
 	if err != nil {
-		return 0, 0, err
+		return
+	}
+	// The checksums of the files, whose targets have checksum placeholders, are calculated once for the whole upload.
+	var targetChecksums *targetChecksumsCache
+	if hasSpecTargetChecksumTokens(uploadSpec) {
+		targetChecksums = newTargetChecksumsCache()
 	}
-	servicesConfig, err := createUploadServiceConfig(configuration.ArtDetails, configuration, certPath, minChecksumDeploySize)
+	plannedUploads, planErr := planUploads(uploadSpec, configuration, changedFilter, targetChecksums)
+	filesCount := countPlannedFiles(plannedUploads)
+
+	// Create Service Manager:
+	specConcurrency := getSpecConcurrency(configuration.SpecConcurrency, len(uploadSpec.Files))
+	servicesConfig, err := createUploadServiceConfig(configuration.ArtDetails, configuration, getUploadThreads(filesCount, configuration))
 	if err != nil {
-		return 0, 0, err
+		return
 	}
 	servicesManager, err := artifactory.New(servicesConfig)
 	if err != nil {
-		return 0, 0, err
+		return
+	}
+	uploaders, err := createSpecUploaders(servicesConfig, changedFilter, specConcurrency, configuration)
+	if err != nil {
+		return
+	}
+	uploadService := uploaders[0].uploadService
+	result.resolvedPaths = uploaders[0].transports.status.resolvedPaths
+	result.checksumDeployed = uploaders[0].transports.status.checksumDeployed
+
+	// Deployment Repository:
+	if configuration.DeployRepo != "" {
+		if err = setDeployRepo(uploadSpec, plannedUploads, configuration.DeployRepo, uploadService); err != nil {
+			return
+		}
+	}
+
+	// Maximum Total Size:
+	if configuration.MaxTotalSizeMB > 0 {
+		if planErr != nil {
+			err = planErr
+			return
+		}
+		if err = validateTotalSize(plannedUploads, configuration.MaxTotalSizeMB); err != nil {
+			return
+		}
+	}
+
+	// Deploy Condition:
+	if configuration.DeployIf != "" {
+		var met bool
+		if met, err = checkDeployCondition(configuration.DeployIf, filesCount, configuration.DryRun, servicesManager); err != nil {
+			return
+		}
+		if !met {
+			result.skippedCount = filesCount
+			return
+		}
 	}
 
 	// Build Info Collection:
 	isCollectBuildInfo := len(configuration.BuildName) > 0 && len(configuration.BuildNumber) > 0
+	isFlushBuildInfo := configuration.BuildFlush && isCollectBuildInfo && !configuration.DryRun
 	if isCollectBuildInfo && !configuration.DryRun {
-		if err := utils.SaveBuildGeneralDetails(configuration.BuildName, configuration.BuildNumber); err != nil {
-			return 0, 0, err
+		if err = startBuildInfoCollection(uploadSpec, configuration); err != nil {
+			return
 		}
-		for i := 0; i < len(uploadSpec.Files); i++ {
-			addBuildProps(&uploadSpec.Get(i).Props, configuration.BuildName, configuration.BuildNumber)
+	}
+
+	// Upload Transports:
+	progress := startUploadProgress(uploaders, filesCount, configuration)
+	if configuration.Events != nil && !configuration.DryRun {
+		wrapEventsTransports(uploaders, configuration)
+	}
+	if targetChecksums != nil {
+		wrapTargetChecksumsTransports(uploaders, targetChecksums)
+	}
+	if maxOpenFiles := getMaxOpenFiles(configuration); maxOpenFiles > 0 && !configuration.DryRun {
+		wrapOpenFilesTransports(uploaders, maxOpenFiles)
+	}
+
+	// Dry Run Output:
+	if configuration.DryRun {
+		if planErr != nil {
+			err = planErr
+			return
+		}
+		if err = reportDryRunUploads(plannedUploads, configuration); err != nil {
+			return
+		}
+		if configuration.AddProps {
+			log.Info("[Dry run] The existing properties of re-uploaded artifacts would be merged with the uploaded properties.")
+		}
+	}
+
+	// Pre-upload Hook:
+	if configuration.PreUploadHook != "" && !configuration.DryRun {
+		if planErr != nil {
+			err = planErr
+			return
+		}
+		if err = runPreUploadHook(plannedUploads, configuration); err != nil {
+			return
 		}
 	}
 
 	// Upload Loop:
-	var filesInfo []clientutils.FileInfo
+	failFast := isFailFast(configuration)
+	if failFast {
+		log.Info("Uploading with the", ErrorModeFailFast, "error mode. The upload stops at the first spec file entry which fails.")
+	} else {
+		log.Debug("Uploading with the", ErrorModeContinue, "error mode.")
+	}
+	entryResults := uploadSpecEntries(uploadSpec, uploaders, failFast, func(i int, uploader *specUploader) specEntryResult {
+		if uploader.transports.getRetriesBudget().isExhausted() {
+			log.Error("Skipping the files of", uploadSpec.Get(i).Pattern+", since the maximum total retries of the upload were exhausted.")
+			return specEntryResult{errorOccurred: true}
+		}
+		entryResult := uploadSpecEntry(uploadSpec.Get(i), i, uploader, signer, sidecarTemplate, servicesManager, configuration)
+		if isFlushBuildInfo {
+			flushSpecEntryBuildInfo(uploadSpec.Get(i), &entryResult, configuration)
+		}
+		return entryResult
+	})
 	var errorOccurred = false
-	for i := 0; i < len(uploadSpec.Files); i++ {
-
-		uploadParams, err := getUploadParams(uploadSpec.Get(i), configuration)
-		if err != nil {
-			errorOccurred = true
-			log.Error(err)
-			continue
+	var fileErrors []*UploadFileError
+	var flushedBytes int64
+	// The index in filesInfo of the first file uploaded by each of the spec files.
+	// The entries which were not uploaded have no files, so that the files are still grouped by their spec files.
+	specStarts := make([]int, len(uploadSpec.Files))
+	for i, entryResult := range entryResults {
+		specStarts[i] = len(result.filesInfo)
+		result.filesInfo = append(result.filesInfo, entryResult.filesInfo...)
+		result.failures = append(result.failures, entryResult.failures...)
+		fileErrors = append(fileErrors, entryResult.fileErrors...)
+		result.successCount += entryResult.successCount
+		result.failCount += entryResult.failCount
+		result.skippedCount += entryResult.skippedCount
+		flushedBytes += entryResult.flushedBytes
+		errorOccurred = errorOccurred || entryResult.errorOccurred
+	}
+	for _, uploader := range uploaders[1:] {
+		for targetPath, resolvedPath := range uploader.transports.status.resolvedPaths {
+			result.resolvedPaths[targetPath] = resolvedPath
+		}
+		for targetPath := range uploader.transports.status.checksumDeployed {
+			result.checksumDeployed[targetPath] = true
+		}
+	}
+	if configuration.SkipExisting && !configuration.DryRun {
+		result.skipped = make(map[string]bool)
+		for _, uploader := range uploaders {
+			for targetPath := range uploader.transports.skipExisting.skippedPaths {
+				result.skipped[targetPath] = true
+			}
+		}
+		if result.skippedCount > 0 {
+			log.Info("Skipped", strconv.Itoa(result.skippedCount), "artifacts, which already exist with the same checksum.")
 		}
+	}
+	if configuration.ChecksumOnlyDeploy {
+		logNotChecksumDeployed(uploaders)
+	}
+	if progress != nil {
+		progress.stop()
+	}
 
-		artifacts, uploaded, failed, err := servicesManager.UploadFiles(uploadParams)
+	// Verification
+	if configuration.VerifyUpload && !configuration.DryRun && len(result.filesInfo) > 0 {
+		fileErrors = append(fileErrors, verifyUploadedFiles(&result, uploaders, configuration)...)
+	}
 
-		filesInfo = append(filesInfo, artifacts...)
-		failCount += failed
-		successCount += uploaded
-		if err != nil {
+	// Post-upload Hook:
+	if configuration.PostUploadHook != "" && !configuration.DryRun && runAndReportPostUploadHook(result.filesInfo, result.failCount, configuration) {
+		errorOccurred = true
+	}
+
+	// Conflicts preview
+	if configuration.PreviewConflicts && configuration.DryRun && len(result.filesInfo) > 0 {
+		if err = printConflictsPreview(result.filesInfo, configuration.ArtDetails.Url, uploadService); err != nil {
 			errorOccurred = true
 			log.Error(err)
-			continue
 		}
 	}
 
-	if errorOccurred {
-		err = errors.New("Upload finished with errors. Please review the logs")
+	if errorOccurred || result.failCount > 0 {
+		err = failUpload(&result, fileErrors, errorOccurred, uploaders, configuration)
 		return
 	}
-	if failCount > 0 {
+	if configuration.FailNoOp && result.successCount+result.skippedCount == 0 {
+		err = ErrNoArtifactsMatched
+		logSyncDeletesSkipped(configuration.SyncDeletes)
 		return
 	}
 
+	// Directory Indexes
+	var indexesInfo []clientutils.FileInfo
+	if configuration.GenerateIndex && len(result.filesInfo) > 0 {
+		var failedIndexes int
+		if indexesInfo, failedIndexes, err = uploadIndexes(result.filesInfo, isCollectBuildInfo, configuration, uploadService); err != nil {
+			return
+		}
+		result.successCount += len(indexesInfo)
+		if failedIndexes > 0 {
+			result.failCount += failedIndexes
+			result.filesInfo = append(result.filesInfo, indexesInfo...)
+			err = failUpload(&result, fileErrors, true, uploaders, configuration)
+			return
+		}
+	}
+
+	// Sync Deletes
+	if configuration.SyncDeletes != "" {
+		// The index files are not deleted, since they list the uploaded artifacts.
+		err = syncDeletes(configuration.SyncDeletes, append(indexesInfo, result.filesInfo...), configuration.ArtDetails.Url, servicesManager)
+		if err != nil {
+			return
+		}
+	}
+
+	// Latest Path
+	if configuration.UpdateLatest != "" && len(result.filesInfo) > 0 {
+		if err = updateLatest(configuration.UpdateLatest, result.filesInfo, configuration.DryRun, uploadService); err != nil {
+			return
+		}
+	}
+
 	// Build Info
 	if isCollectBuildInfo && !configuration.DryRun {
-		buildArtifacts := convertFileInfoToBuildArtifacts(filesInfo)
-		populateFunc := func(partial *buildinfo.Partial) {
-			partial.Artifacts = buildArtifacts
+		uploadStats := createUploadStats(result.filesInfo, time.Since(startTime))
+		if isFlushBuildInfo {
+			uploadStats = createUploadStatsFromBytes(flushedBytes, time.Since(startTime))
 		}
-		err = utils.SavePartialBuildInfo(configuration.BuildName, configuration.BuildNumber, populateFunc)
+		if expiry != "" {
+			uploadStats[ExpiryProp] = expiry
+		}
+		err = saveBuildInfo(uploadSpec, result.filesInfo, specStarts, indexesInfo, uploadStats, isFlushBuildInfo, configuration, uploadService)
 	}
+	result.filesInfo = append(result.filesInfo, indexesInfo...)
 	return
 }
 
-func convertFileInfoToBuildArtifacts(filesInfo []clientutils.FileInfo) []buildinfo.Artifact {
-	buildArtifacts := make([]buildinfo.Artifact, len(filesInfo))
-	for i, fileInfo := range filesInfo {
-		buildArtifacts[i] = fileInfo.ToBuildArtifacts()
+// Returns the error of an upload in which files failed to upload, or errors occurred. If the upload is transactional,
+// it is rolled back, and the artifacts which were deleted are removed from the result.
+func failUpload(result *uploadFilesResult, fileErrors []*UploadFileError, errorOccurred bool, uploaders []*specUploader, configuration *UploadConfiguration) error {
+	err := newUploadError(result.failCount, fileErrors, errorOccurred)
+	logSyncDeletesSkipped(configuration.SyncDeletes)
+	if configuration.Transactional && !configuration.DryRun {
+		var rolledBack int
+		result.filesInfo, rolledBack = rollbackFailedUpload(err, result.filesInfo, uploaders, uploaders[0].uploadService, configuration)
+		result.successCount -= rolledBack
 	}
-	return buildArtifacts
+	return err
 }
 
-func createUploadServiceConfig(artDetails *config.ArtifactoryDetails, flags *UploadConfiguration, certPath string, minChecksumDeploySize int64) (artifactory.Config, error) {
-	artAuth, err := artDetails.CreateArtAuthConfig()
-	if err != nil {
-		return nil, err
+// Returns true if the checksums of the local files should be calculated by reading their content, when they are not
+// known otherwise. Dry runs skip these calculations to stay fast, unless configuration.DryRunComputeChecksums is set.
+func isCalcChecksums(configuration *UploadConfiguration) bool {
+	return !configuration.DryRun || configuration.DryRunComputeChecksums
+}
+
+// Returns the log record of an error in the upload of the spec file entry, with the pattern and the target of the entry
+// as the file and target fields.
+func createUploadErrorRecord(err error, f *spec.File) cliutils.LogRecord {
+	return cliutils.LogRecord{Message: err.Error(), Fields: map[string]string{"file": f.Pattern, "target": f.Target, "error": err.Error()}}
+}
+
+// Logs each of the files which failed to upload, with its source and target as fields, when the log is written as JSON.
+// The text log includes only the errors reported by the upload service.
+func logFailedUploads(failedUploads []UploadResult) {
+	if !cliutils.IsJsonLog() {
+		return
+	}
+	for _, failedUpload := range failedUploads {
+		log.Error(cliutils.LogRecord{Message: "Failed uploading " + failedUpload.LocalPath, Fields: map[string]string{"file": failedUpload.LocalPath, "target": failedUpload.TargetPath}})
+	}
+}
+
+func getRelativeTargetPath(artifactoryPath, artifactoryUrl string) string {
+	targetPath := strings.TrimPrefix(artifactoryPath, artifactoryUrl)
+	if unescapedPath, err := url.PathUnescape(targetPath); err == nil {
+		targetPath = unescapedPath
+	}
+	return targetPath
+}
+
+// Returns the number of files, which are expected to be uploaded by the planned uploads.
+func countPlannedFiles(plannedUploads []DryRunUpload) (count int) {
+	for _, plannedUpload := range plannedUploads {
+		if !plannedUpload.isDir {
+			count++
+		}
 	}
-	servicesConfig, err := artifactory.NewConfigBuilder().
-		SetArtDetails(artAuth).
-		SetDryRun(flags.DryRun).
-		SetCertificatesPath(certPath).
-		SetMinChecksumDeploy(minChecksumDeploySize).
-		SetThreads(flags.Threads).
-		SetLogger(log.Logger).
-		Build()
-	return servicesConfig, err
+	return
 }
 
-func getMinChecksumDeploySize() (int64, error) {
+// Returns the minimum file size in bytes for checksum deploy, as set by the JFROG_CLI_MIN_CHECKSUM_DEPLOY_SIZE_KB environment variable.
+func GetMinChecksumDeploySize() (int64, error) {
 	minChecksumDeploySize := os.Getenv("JFROG_CLI_MIN_CHECKSUM_DEPLOY_SIZE_KB")
 	if minChecksumDeploySize == "" {
 		return 10240, nil
@@ -126,50 +486,36 @@ func getMinChecksumDeploySize() (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	if minSize < 0 {
+		return 0, errorutils.CheckError(errors.New("JFROG_CLI_MIN_CHECKSUM_DEPLOY_SIZE_KB cannot be negative: " + minChecksumDeploySize))
+	}
 	return minSize * 1000, nil
 }
 
-func addBuildProps(props *string, buildName, buildNumber string) error {
-	if buildName == "" || buildNumber == "" {
-		return nil
-	}
-	buildProps, err := utils.CreateBuildProperties(buildName, buildNumber)
-	if err != nil {
-		return err
-	}
-
-	if len(*props) > 0 && !strings.HasSuffix(*props, ";") && len(buildProps) > 0 {
+// Appends the new props to the props, separated by ';'.
+func addProps(props *string, newProps string) {
+	if len(*props) > 0 && !strings.HasSuffix(*props, ";") && len(newProps) > 0 {
 		*props += ";"
 	}
-	*props += buildProps
-	return nil
-}
-
-type UploadConfiguration struct {
-	Deb                   string
-	Threads               int
-	MinChecksumDeploySize int64
-	BuildName             string
-	BuildNumber           string
-	DryRun                bool
-	Symlink               bool
-	ExplodeArchive        bool
-	ArtDetails            *config.ArtifactoryDetails
-	Retries               int
+	*props += newProps
 }
 
 func getUploadParams(f *spec.File, configuration *UploadConfiguration) (uploadParams services.UploadParams, err error) {
 	uploadParams = services.NewUploadParams()
 	uploadParams.ArtifactoryCommonParams = f.ToArtifactoryCommonParams()
+	uploadParams.Target = expandTargetDateTokens(uploadParams.Target, configuration.TargetTime)
 	uploadParams.Recursive, err = f.IsRecursive(true)
 	if err != nil {
 		return
 	}
 
-	uploadParams.Regexp, err = f.IsRegexp(false)
+	patternType, err := f.GetPatternType()
 	if err != nil {
 		return
 	}
+	if err = setUploadPattern(&uploadParams, patternType); err != nil {
+		return
+	}
 
 	uploadParams.IncludeDirs, err = f.IsIncludeDirs(false)
 	if err != nil {
@@ -186,8 +532,20 @@ func getUploadParams(f *spec.File, configuration *UploadConfiguration) (uploadPa
 		return
 	}
 
-	uploadParams.Deb = configuration.Deb
+	uploadParams.Deb, err = getDebConfig(configuration)
+	if err != nil {
+		return
+	}
 	uploadParams.Symlink = configuration.Symlink
-	uploadParams.Retries = configuration.Retries
+	uploadParams.Retries = getUploadRetries(configuration)
 	return
 }
+
+// Returns the retries of the upload service.
+func getUploadRetries(configuration *UploadConfiguration) int {
+	if configuration.RetriesSizeScalingMB > 0 {
+		// The retries of each file are limited according to its size by the retries limit transport.
+		return configuration.MaxRetries
+	}
+	return configuration.Retries
+}
@@ -0,0 +1,115 @@
+package generic
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Returns the props of the artifacts in Artifactory, which are about to be overwritten by the upload, keyed by target path.
+func getExistingProps(f *spec.File, uploadParams services.UploadParams, uploadService *services.UploadService, checksums *targetChecksumsCache) (map[string]map[string][]string, error) {
+	files, err := getUploadFiles(f, uploadParams, checksums)
+	if err != nil {
+		return nil, err
+	}
+	existingProps := make(map[string]map[string][]string)
+	for _, file := range files {
+		if file.isDir {
+			continue
+		}
+		props, err := getArtifactProps(file.targetPath, uploadService)
+		if err != nil {
+			return nil, err
+		}
+		if len(props) > 0 {
+			existingProps[file.targetPath] = props
+		}
+	}
+	return existingProps, nil
+}
+
+// Returns the props of the artifact in the target path, or nil if the artifact does not exist or has no props.
+func getArtifactProps(targetPath string, uploadService *services.UploadService) (map[string][]string, error) {
+	propsUrl, err := clientutils.BuildArtifactoryUrl(uploadService.ArtDetails.GetUrl(), "api/storage/"+targetPath, map[string]string{"properties": ""})
+	if err != nil {
+		return nil, err
+	}
+	resp, body, _, err := uploadService.GetJfrogHttpClient().SendGet(propsUrl, true, uploadService.ArtDetails.CreateHttpClientDetails())
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorutils.CheckError(errors.New("Failed reading the properties of " + targetPath + ". Artifactory response: " + resp.Status))
+	}
+	result := new(struct {
+		Properties map[string][]string `json:"properties"`
+	})
+	err = json.Unmarshal(body, result)
+	return result.Properties, errorutils.CheckError(err)
+}
+
+// Sets the existing props, which were removed by the upload, back on the uploaded artifacts.
+// Existing props with the same key as one of the uploaded props are not set back, so that the uploaded value is kept.
+// The props of each of the artifacts are set by a single request, limited by the limiter.
+func mergeExistingProps(existingProps map[string]map[string][]string, uploadedProps string, filesInfo []clientutils.FileInfo, artifactoryUrl string, servicesManager *artifactory.ArtifactoryServicesManager, limiter *requestRateLimiter) error {
+	if len(existingProps) == 0 {
+		return nil
+	}
+	properties, err := clientutils.ParseProperties(uploadedProps, clientutils.JoinCommas)
+	if err != nil {
+		return err
+	}
+	uploadedKeys := make(map[string]bool)
+	for _, property := range properties.Properties {
+		uploadedKeys[property.Key] = true
+	}
+	for _, fileInfo := range filesInfo {
+		targetPath := getRelativeTargetPath(fileInfo.ArtifactoryPath, artifactoryUrl)
+		props := createMergedPropsString(existingProps[targetPath], uploadedKeys)
+		if props == "" {
+			continue
+		}
+		log.Debug("Merging the existing properties of", targetPath+":", props)
+		limiter.wait()
+		if _, err := servicesManager.SetProps(services.PropsParams{Items: []clientutils.ResultItem{createResultItem(targetPath)}, Props: props}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the existing props, excluding the uploaded keys, in the form of "key1=value1,value2;key2=value3".
+func createMergedPropsString(existingProps map[string][]string, uploadedKeys map[string]bool) string {
+	var props []string
+	for key, values := range existingProps {
+		if !uploadedKeys[key] {
+			props = append(props, key+"="+strings.Join(values, ","))
+		}
+	}
+	sort.Strings(props)
+	return strings.Join(props, ";")
+}
+
+func createResultItem(targetPath string) clientutils.ResultItem {
+	repo, repoPath := targetPath, "."
+	if i := strings.Index(targetPath, "/"); i >= 0 {
+		repo, repoPath = targetPath[:i], targetPath[i+1:]
+	}
+	dir, name := path.Split(repoPath)
+	if dir = strings.TrimSuffix(dir, "/"); dir == "" {
+		dir = "."
+	}
+	return clientutils.ResultItem{Repo: repo, Path: dir, Name: name}
+}
@@ -0,0 +1,163 @@
+package generic
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The types of archives, which can be created from the files matching a file spec and uploaded as a single artifact.
+const (
+	ArchiveZip   = "zip"
+	ArchiveTar   = "tar"
+	ArchiveTarGz = "tar.gz"
+)
+
+func isArchiveType(archiveType string) bool {
+	return archiveType == ArchiveZip || archiveType == ArchiveTar || archiveType == ArchiveTarGz
+}
+
+// Packages the files matching the upload params into an archive of the specified type, and uploads it to the exact
+// target path of the upload params. The archive is streamed to Artifactory while it is created, so it is never stored
+// on disk or buffered in memory. Since the archive is created only once, the upload is not retried.
+// In a dry run, the archive is created only if calcChecksums is set, to calculate its checksums.
+func uploadArchive(archiveType string, uploadParams services.UploadParams, uploadService *services.UploadService, calcChecksums bool) (*clientutils.FileInfo, error) {
+	if !isArchiveType(archiveType) {
+		return nil, errorutils.CheckError(errors.New("The archive type should be one of: zip, tar or tar.gz, but got: " + archiveType))
+	}
+	files, err := collectFilesForUpload(uploadParams, nil)
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+	description := "a " + archiveType + " archive of " + uploadParams.GetPattern()
+	if uploadService.DryRun && !calcChecksums {
+		fileInfo, err := uploadStream(uploadParams.GetPattern(), description, nil, uploadParams, uploadService)
+		if err != nil {
+			return nil, err
+		}
+		return &fileInfo, nil
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeArchive(writer, archiveType, files, uploadParams.IsFlat()))
+	}()
+	// Unblocks the archive creation, if the upload stops reading before the archive is complete.
+	defer reader.Close()
+	fileInfo, err := uploadStream(uploadParams.GetPattern(), description, reader, uploadParams, uploadService)
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo, nil
+}
+
+// Same as uploadArchive, but returns the results in the form returned by the upload service.
+func uploadArchiveFile(archiveType string, uploadParams services.UploadParams, uploadService *services.UploadService, calcChecksums bool) ([]clientutils.FileInfo, int, int, error) {
+	fileInfo, err := uploadArchive(archiveType, uploadParams, uploadService, calcChecksums)
+	if err != nil {
+		return nil, 0, 1, err
+	}
+	if fileInfo == nil {
+		return nil, 0, 0, nil
+	}
+	return []clientutils.FileInfo{*fileInfo}, 1, 0, nil
+}
+
+func writeArchive(writer io.Writer, archiveType string, files []uploadFile, isFlat bool) error {
+	switch archiveType {
+	case ArchiveZip:
+		return writeZipArchive(writer, files, isFlat)
+	case ArchiveTarGz:
+		gzipWriter := gzip.NewWriter(writer)
+		if err := writeTarArchive(gzipWriter, files, isFlat); err != nil {
+			return err
+		}
+		return errorutils.CheckError(gzipWriter.Close())
+	default:
+		return writeTarArchive(writer, files, isFlat)
+	}
+}
+
+func writeZipArchive(writer io.Writer, files []uploadFile, isFlat bool) error {
+	zipWriter := zip.NewWriter(writer)
+	for _, file := range files {
+		info, err := os.Stat(file.localPath)
+		if errorutils.CheckError(err) != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if errorutils.CheckError(err) != nil {
+			return err
+		}
+		header.Name = getArchiveEntryName(file.localPath, isFlat)
+		if file.isDir {
+			header.Name += "/"
+		} else {
+			header.Method = zip.Deflate
+		}
+		entryWriter, err := zipWriter.CreateHeader(header)
+		if errorutils.CheckError(err) != nil {
+			return err
+		}
+		if !file.isDir {
+			if err = copyFileContent(entryWriter, file.localPath); err != nil {
+				return err
+			}
+		}
+	}
+	return errorutils.CheckError(zipWriter.Close())
+}
+
+func writeTarArchive(writer io.Writer, files []uploadFile, isFlat bool) error {
+	tarWriter := tar.NewWriter(writer)
+	for _, file := range files {
+		info, err := os.Stat(file.localPath)
+		if errorutils.CheckError(err) != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if errorutils.CheckError(err) != nil {
+			return err
+		}
+		header.Name = getArchiveEntryName(file.localPath, isFlat)
+		if file.isDir {
+			header.Name += "/"
+		}
+		if err = errorutils.CheckError(tarWriter.WriteHeader(header)); err != nil {
+			return err
+		}
+		if !file.isDir {
+			if err = copyFileContent(tarWriter, file.localPath); err != nil {
+				return err
+			}
+		}
+	}
+	return errorutils.CheckError(tarWriter.Close())
+}
+
+func copyFileContent(writer io.Writer, localPath string) error {
+	file, err := os.Open(localPath)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(writer, file)
+	return errorutils.CheckError(err)
+}
+
+// Returns the path of the file inside the archive, while taking the 'flat' option into account,
+// the same way the target path of the file is constructed when uploading it.
+func getArchiveEntryName(localPath string, isFlat bool) string {
+	if isFlat {
+		return filepath.Base(localPath)
+	}
+	return strings.TrimPrefix(utils.TrimPath(localPath), "/")
+}
@@ -0,0 +1,117 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Saves the general details of the collected build, and adds the build props to the props of the spec file entries.
+func startBuildInfoCollection(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) error {
+	if err := utils.SaveBuildGeneralDetails(configuration.BuildName, configuration.BuildNumber); err != nil {
+		return err
+	}
+	if configuration.Project != "" {
+		if err := utils.SaveBuildProject(configuration.BuildName, configuration.BuildNumber, configuration.Project); err != nil {
+			return err
+		}
+	}
+	// The artifacts are associated with the build by their checksums, so the build props are optional.
+	for i := 0; i < len(uploadSpec.Files) && !configuration.NoBuildProps; i++ {
+		if err := addBuildProps(&uploadSpec.Get(i).Props, configuration.BuildName, configuration.BuildNumber, configuration.SkipBuildTimestampProp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Saves the uploaded artifacts and the upload stats to the build info, and uploads the build info artifact if
+// configuration.BuildInfoTarget is set. specStarts holds the index in filesInfo of the first file uploaded by each of
+// the spec file entries. The index files are saved as artifacts of the module of the first spec file entry.
+// If the artifacts were already saved after each of the entries, only the upload stats are saved.
+func saveBuildInfo(uploadSpec *spec.SpecFiles, filesInfo []clientutils.FileInfo, specStarts []int, indexesInfo []clientutils.FileInfo, uploadStats buildinfo.Env, isFlushed bool, configuration *UploadConfiguration, uploadService *services.UploadService) error {
+	var modules []*uploadModule
+	if !isFlushed {
+		modules = groupByModule(uploadSpec, filesInfo, specStarts)
+	}
+	if len(indexesInfo) > 0 {
+		if len(modules) == 0 {
+			modules = []*uploadModule{{id: uploadSpec.Get(0).Module}}
+		}
+		modules[0].artifacts = append(modules[0].artifacts, indexesInfo...)
+	}
+	partials, err := saveUploadBuildInfo(modules, uploadStats, configuration.BuildName, configuration.BuildNumber, !configuration.NoSortArtifacts, configuration.BuildAppend || isFlushed)
+	if err != nil || configuration.BuildInfoTarget == "" {
+		return err
+	}
+	// The build info artifact is not an artifact of the build, so it is not included in the results.
+	buildInfoProps := ""
+	if !configuration.NoBuildProps {
+		if err = addBuildProps(&buildInfoProps, configuration.BuildName, configuration.BuildNumber, configuration.SkipBuildTimestampProp); err != nil {
+			return err
+		}
+	}
+	_, err = uploadBuildInfoArtifact(configuration.BuildInfoTarget, partials, buildInfoProps, configuration, uploadService)
+	return err
+}
+
+// Appends the build properties to the props. The build.timestamp property is the start time of the build,
+// as saved in the general build details, unless skipTimestamp is set.
+func addBuildProps(props *string, buildName, buildNumber string, skipTimestamp bool) error {
+	if buildName == "" || buildNumber == "" {
+		return nil
+	}
+	if skipTimestamp {
+		addProps(props, utils.CreateBuildPropertiesWithoutTimestamp(buildName, buildNumber))
+		return nil
+	}
+	buildProps, err := utils.CreateBuildProperties(buildName, buildNumber)
+	if err != nil {
+		return err
+	}
+	addProps(props, buildProps)
+	return nil
+}
+
+func convertFileInfoToBuildArtifacts(filesInfo []clientutils.FileInfo) []buildinfo.Artifact {
+	buildArtifacts := make([]buildinfo.Artifact, len(filesInfo))
+	for i, fileInfo := range filesInfo {
+		buildArtifacts[i] = fileInfo.ToBuildArtifacts()
+	}
+	return buildArtifacts
+}
+
+// Returns the total size of the uploaded files, the duration of the upload and its average throughput,
+// as build properties. The sizes of the uploads from stdin and of archives created on the fly are unknown.
+func createUploadStats(filesInfo []clientutils.FileInfo, elapsed time.Duration) buildinfo.Env {
+	return createUploadStatsFromBytes(getUploadedBytes(filesInfo), elapsed)
+}
+
+// Returns the total size of the uploaded files. The sizes of the uploads from stdin and of archives created on the fly are unknown.
+func getUploadedBytes(filesInfo []clientutils.FileInfo) (totalBytes int64) {
+	for _, fileInfo := range filesInfo {
+		if stat, err := os.Stat(fileInfo.LocalPath); err == nil && stat.Mode().IsRegular() {
+			totalBytes += stat.Size()
+		}
+	}
+	return
+}
+
+// Same as createUploadStats, but with the total size of the uploaded files.
+func createUploadStatsFromBytes(totalBytes int64, elapsed time.Duration) buildinfo.Env {
+	elapsedMillis := int64(elapsed / time.Millisecond)
+	var throughput int64
+	if elapsedMillis > 0 {
+		throughput = totalBytes * 1000 / elapsedMillis
+	}
+	return buildinfo.Env{
+		UploadBytesProp:      strconv.FormatInt(totalBytes, 10),
+		UploadDurationProp:   strconv.FormatInt(elapsedMillis, 10),
+		UploadThroughputProp: strconv.FormatInt(throughput, 10),
+	}
+}
@@ -0,0 +1,40 @@
+package generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"strings"
+)
+
+// The content of the build info artifact, with the partial build infos saved by the upload.
+type buildInfoArtifact struct {
+	Name     string               `json:"name"`
+	Number   string               `json:"number"`
+	Partials []*buildinfo.Partial `json:"partials"`
+}
+
+// Returns the target path of the build info artifact. If the target ends with a slash, the artifact is named after
+// the build name and number.
+func getBuildInfoArtifactTarget(target, buildName, buildNumber string) string {
+	if strings.HasSuffix(target, "/") {
+		return target + buildName + "-" + buildNumber + ".json"
+	}
+	return target
+}
+
+// Uploads the partial build infos saved by the upload as a JSON artifact, so that a copy of the build info of the
+// upload is archived alongside the uploaded artifacts. Returns the details of the uploaded artifact.
+func uploadBuildInfoArtifact(target string, partials []*buildinfo.Partial, props string, configuration *UploadConfiguration, uploadService *services.UploadService) (clientutils.FileInfo, error) {
+	content, err := json.MarshalIndent(buildInfoArtifact{Name: configuration.BuildName, Number: configuration.BuildNumber, Partials: partials}, "", "  ")
+	if errorutils.CheckError(err) != nil {
+		return clientutils.FileInfo{}, err
+	}
+	buildInfoParams := services.NewUploadParams()
+	buildInfoParams.ArtifactoryCommonParams = &clientutils.ArtifactoryCommonParams{Target: getBuildInfoArtifactTarget(target, configuration.BuildName, configuration.BuildNumber), Props: props}
+	// The build info artifact has no local path, since it is serialized in memory.
+	return uploadStream("", "the build info of "+configuration.BuildName+"/"+configuration.BuildNumber, bytes.NewReader(content), buildInfoParams, uploadService)
+}
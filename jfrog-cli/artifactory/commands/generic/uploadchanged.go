@@ -0,0 +1,238 @@
+package generic
+
+import (
+	"bytes"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Filters the files matched by the upload spec, so that only the files which changed are uploaded.
+// A file is changed if it was modified after modifiedAfter, and if it was changed according to git, when set.
+type changedFilesFilter struct {
+	modifiedAfter time.Time
+	// The absolute paths of the files changed since the git ref, or nil if the files are not filtered by git.
+	gitChangedPaths map[string]bool
+}
+
+// Returns nil if the files should not be filtered.
+func newChangedFilesFilter(modifiedAfter time.Time, changedSince string) (*changedFilesFilter, error) {
+	if modifiedAfter.IsZero() && changedSince == "" {
+		return nil, nil
+	}
+	filter := &changedFilesFilter{modifiedAfter: modifiedAfter}
+	if changedSince != "" {
+		var err error
+		if filter.gitChangedPaths, err = getGitChangedPaths(changedSince); err != nil {
+			return nil, err
+		}
+		log.Info("Uploading only the files changed since", changedSince+".", strconv.Itoa(len(filter.gitChangedPaths)), "files were changed.")
+	}
+	if !modifiedAfter.IsZero() {
+		log.Info("Uploading only the files modified after", modifiedAfter.Format(time.RFC3339)+".")
+	}
+	return filter, nil
+}
+
+func (filter *changedFilesFilter) isChanged(localPath string) (bool, error) {
+	if !filter.modifiedAfter.IsZero() {
+		info, err := os.Stat(localPath)
+		if errorutils.CheckError(err) != nil {
+			return false, err
+		}
+		if !info.ModTime().After(filter.modifiedAfter) {
+			return false, nil
+		}
+	}
+	if filter.gitChangedPaths != nil {
+		path, err := resolveChangedPath(localPath)
+		if err != nil {
+			return false, err
+		}
+		return filter.gitChangedPaths[path], nil
+	}
+	return true, nil
+}
+
+// Returns the files which changed. Directories are always kept, since they have no content to compare.
+func filterChangedFiles(files []uploadFile, filter *changedFilesFilter) ([]uploadFile, error) {
+	if filter == nil {
+		return files, nil
+	}
+	var changedFiles []uploadFile
+	for _, file := range files {
+		changed := file.isDir
+		if !changed {
+			var err error
+			if changed, err = filter.isChanged(file.localPath); err != nil {
+				return nil, err
+			}
+		}
+		if changed {
+			changedFiles = append(changedFiles, file)
+		}
+	}
+	return changedFiles, nil
+}
+
+// Returns the absolute paths of the files changed in the working tree since the git ref, including the untracked files.
+func getGitChangedPaths(ref string) (map[string]bool, error) {
+	topLevel, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := runGit("diff", "--name-only", "--no-renames", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := runGit("ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[string]bool)
+	for _, name := range strings.Split(diff+"\n"+untracked, "\n") {
+		if name == "" {
+			continue
+		}
+		path, err := resolveChangedPath(filepath.Join(topLevel, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+		paths[path] = true
+	}
+	return paths, nil
+}
+
+func runGit(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errorutils.CheckError(errors.New("Failed running 'git " + strings.Join(args, " ") + "': " + strings.TrimSpace(err.Error()+" "+stderr.String())))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Returns the absolute path of the file, with the symlinks of its directory resolved, so that the paths listed by git
+// can be compared with the paths matched by the upload spec.
+func resolveChangedPath(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		// The directory of a deleted file no longer exists.
+		return path, nil
+	}
+	return filepath.Join(dir, filepath.Base(path)), nil
+}
+
+// An http.RoundTripper, which skips the upload of the files which did not change.
+// The skipped uploads are answered as if they were successful, and are then removed from the uploaded files,
+// so that they are neither counted nor recorded in the build info.
+type unchangedFilesTransport struct {
+	transport      http.RoundTripper
+	filter         *changedFilesFilter
+	artifactoryUrl string
+	mutex          sync.Mutex
+	// The target URL paths of the unchanged files of the spec file currently being uploaded.
+	unchanged map[string]bool
+	skipped   map[string]bool
+}
+
+func (ut *unchangedFilesTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	targetPath := strings.SplitN(req.URL.Path, ";", 2)[0]
+	if req.Method != http.MethodPut || !ut.unchanged[targetPath] {
+		return ut.transport.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	ut.mutex.Lock()
+	ut.skipped[targetPath] = true
+	ut.mutex.Unlock()
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      req.Proto,
+		ProtoMajor: req.ProtoMajor,
+		ProtoMinor: req.ProtoMinor,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+// Resolves the unchanged files of the upload params, before they are uploaded.
+func (ut *unchangedFilesTransport) setUploadParams(uploadParams services.UploadParams, checksums *targetChecksumsCache) error {
+	files, err := collectFilesForUpload(uploadParams, checksums)
+	if err != nil {
+		return err
+	}
+	ut.unchanged = make(map[string]bool)
+	ut.skipped = make(map[string]bool)
+	for _, file := range files {
+		if file.isDir {
+			continue
+		}
+		changed, err := ut.filter.isChanged(file.localPath)
+		if err != nil {
+			return err
+		}
+		if changed {
+			continue
+		}
+		targetPath, err := getTargetUrlPath(ut.artifactoryUrl, file.targetPath)
+		if err != nil {
+			return err
+		}
+		ut.unchanged[targetPath] = true
+	}
+	return nil
+}
+
+// Removes the skipped unchanged files from the uploaded artifacts, and returns the number of removed artifacts.
+func (ut *unchangedFilesTransport) removeSkipped(artifacts []clientutils.FileInfo) ([]clientutils.FileInfo, int) {
+	if len(ut.skipped) == 0 {
+		return artifacts, 0
+	}
+	var changedArtifacts []clientutils.FileInfo
+	for _, artifact := range artifacts {
+		targetUrl, err := url.Parse(artifact.ArtifactoryPath)
+		if err == nil && ut.skipped[targetUrl.Path] {
+			continue
+		}
+		changedArtifacts = append(changedArtifacts, artifact)
+	}
+	removed := len(artifacts) - len(changedArtifacts)
+	if removed > 0 {
+		log.Info("Skipped the upload of", strconv.Itoa(removed), "unchanged files.")
+	}
+	return changedArtifacts, removed
+}
+
+func getTargetUrlPath(artifactoryUrl, targetPath string) (string, error) {
+	targetUrl, err := clientutils.BuildArtifactoryUrl(artifactoryUrl, targetPath, make(map[string]string))
+	if err != nil {
+		return "", err
+	}
+	parsedUrl, err := url.Parse(targetUrl)
+	if errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	return parsedUrl.Path, nil
+}
@@ -0,0 +1,143 @@
+package generic
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const checksumCacheFile = "sha256-checksums.json"
+
+// The SHA256 checksum of a local file, calculated when the file had the specified size and modification time.
+type cachedSha256 struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"modTime"`
+	Sha256  string `json:"sha256"`
+}
+
+// The SHA256 checksums of the local files calculated by previous uploads, keyed by the absolute paths of the files.
+// A checksum is reused only while the size and modification time of its file are unchanged.
+// The methods of a nil cache calculate the checksums without caching them.
+type checksumCache struct {
+	path     string
+	mutex    sync.Mutex
+	modified bool
+	Files    map[string]cachedSha256 `json:"files"`
+}
+
+// The checksum cache of the running upload, used by calcSha256. Nil if the upload has no checksum cache.
+var activeChecksumCache struct {
+	sync.Mutex
+	cache *checksumCache
+}
+
+func loadChecksumCache(dir string) (*checksumCache, error) {
+	cache := &checksumCache{path: filepath.Join(dir, checksumCacheFile), Files: make(map[string]cachedSha256)}
+	if !fileutils.IsPathExists(cache.path, false) {
+		return cache, nil
+	}
+	content, err := ioutil.ReadFile(cache.path)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	// A corrupted cache is discarded rather than failing the upload, since its checksums can be calculated again.
+	if err = json.Unmarshal(content, cache); err != nil {
+		log.Warn("Ignoring the corrupted checksum cache", cache.path+":", err.Error())
+		cache.Files = nil
+	}
+	if cache.Files == nil {
+		cache.Files = make(map[string]cachedSha256)
+	}
+	return cache, nil
+}
+
+func setActiveChecksumCache(cache *checksumCache) {
+	activeChecksumCache.Lock()
+	activeChecksumCache.cache = cache
+	activeChecksumCache.Unlock()
+}
+
+func getActiveChecksumCache() *checksumCache {
+	activeChecksumCache.Lock()
+	defer activeChecksumCache.Unlock()
+	return activeChecksumCache.cache
+}
+
+// Returns the SHA256 checksum of the local file, reading its content only if the checksum is not cached or the file
+// changed since it was cached.
+func (cache *checksumCache) getSha256(localPath string) (string, error) {
+	if cache == nil {
+		return readSha256(localPath)
+	}
+	absPath, err := filepath.Abs(localPath)
+	if errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	stat, err := os.Stat(absPath)
+	if errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	cache.mutex.Lock()
+	cached, ok := cache.Files[absPath]
+	cache.mutex.Unlock()
+	if ok && cached.Size == stat.Size() && cached.ModTime == stat.ModTime().UnixNano() {
+		return cached.Sha256, nil
+	}
+	sha256, err := readSha256(localPath)
+	if err != nil {
+		return "", err
+	}
+	cache.mutex.Lock()
+	cache.Files[absPath] = cachedSha256{Size: stat.Size(), ModTime: stat.ModTime().UnixNano(), Sha256: sha256}
+	cache.modified = true
+	cache.mutex.Unlock()
+	return sha256, nil
+}
+
+// Writes the cache to its file, if any of its checksums were calculated since it was loaded.
+func (cache *checksumCache) save() error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if !cache.modified {
+		return nil
+	}
+	content, err := json.Marshal(cache)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(cache.path), 0700); errorutils.CheckError(err) != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(cache.path, content, 0600); errorutils.CheckError(err) != nil {
+		return err
+	}
+	cache.modified = false
+	return nil
+}
+
+// Returns the SHA256 checksum of the local file, reusing the checksum cached by a previous upload if the upload has
+// a checksum cache.
+func calcSha256(localPath string) (string, error) {
+	return getActiveChecksumCache().getSha256(localPath)
+}
+
+func readSha256(localPath string) (string, error) {
+	file, err := os.Open(localPath)
+	if errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
@@ -0,0 +1,204 @@
+package generic
+
+import (
+	"bytes"
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The checksum algorithms, by which artifacts are deployed by checksum.
+const (
+	// Deploy by the SHA-256 checksum, falling back to the SHA-1 checksum if the SHA-256 checksum deploy fails.
+	ChecksumAlgorithmSha256 = "sha256"
+	// Deploy by the SHA-1 checksum only.
+	ChecksumAlgorithmSha1 = "sha1"
+)
+
+// An http.RoundTripper, which sends the checksum deploy requests with the checksum of the configured algorithm.
+// The upload service sends only the SHA-1 checksum of the files, so their SHA-256 checksum is calculated from the
+// local file of the target path.
+// The checksum deploy requests of the files whose names match the no checksum deploy patterns are not sent. Instead,
+// they fail, so that the files are uploaded in full.
+// When deploying only by checksum, the files are deployed by their SHA-256 checksum regardless of their size, and the
+// uploads of the files which could not be deployed by checksum fail without sending their content.
+type checksumDeployTransport struct {
+	transport                http.RoundTripper
+	algorithm                string
+	noChecksumDeployPatterns []string
+	checksumOnly             bool
+	// The local paths of the files of the spec file currently being uploaded, keyed by target URL path.
+	// Set between the uploads of the spec files, only when deploying by the SHA-256 checksum.
+	files map[string]string
+	mutex sync.Mutex
+	// The local paths of the files, which could not be deployed only by checksum.
+	notDeployed []string
+}
+
+func (cdt *checksumDeployTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPut {
+		return cdt.transport.RoundTrip(req)
+	}
+	if req.Header.Get("X-Checksum-Deploy") != "true" {
+		localPath, ok := cdt.files[strings.SplitN(req.URL.Path, ";", 2)[0]]
+		if !cdt.checksumOnly || !ok {
+			return cdt.transport.RoundTrip(req)
+		}
+		// The checksum deploy of the file failed, so its content is not uploaded.
+		log.Debug("Not uploading", localPath+", since Artifactory does not have its content.")
+		cdt.mutex.Lock()
+		cdt.notDeployed = append(cdt.notDeployed, localPath)
+		cdt.mutex.Unlock()
+		return newChecksumNotFoundResponse(req), nil
+	}
+	if name := path.Base(strings.SplitN(req.URL.Path, ";", 2)[0]); isNoChecksumDeploy(name, cdt.noChecksumDeployPatterns) {
+		log.Debug("Uploading", name, "in full, since it matches the no checksum deploy patterns.")
+		return newChecksumNotFoundResponse(req), nil
+	}
+	sha1 := req.Header.Get("X-Checksum-Sha1")
+	if cdt.algorithm == ChecksumAlgorithmSha1 {
+		return cdt.transport.RoundTrip(withChecksumHeaders(req, "", sha1))
+	}
+	sha256 := req.Header.Get("X-Checksum")
+	if localPath, ok := cdt.files[strings.SplitN(req.URL.Path, ";", 2)[0]]; ok && sha256 == "" {
+		var err error
+		if sha256, err = calcSha256(localPath); err != nil {
+			log.Debug("Failed calculating the SHA-256 checksum of", localPath+":", err.Error())
+		}
+	}
+	if sha256 == "" && cdt.checksumOnly {
+		return newChecksumNotFoundResponse(req), nil
+	}
+	if sha256 == "" {
+		return cdt.transport.RoundTrip(withChecksumHeaders(req, "", sha1))
+	}
+	resp, err := cdt.transport.RoundTrip(withChecksumHeaders(req, sha256, ""))
+	if err != nil || resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated || cdt.checksumOnly {
+		return resp, err
+	}
+	// Artifactory may not support the SHA-256 checksum deploy, so the SHA-1 checksum is tried before the file itself is uploaded.
+	resp.Body.Close()
+	return cdt.transport.RoundTrip(withChecksumHeaders(req, "", sha1))
+}
+
+// Resolves the local files of the upload params, before they are uploaded.
+func (cdt *checksumDeployTransport) setUploadParams(uploadParams services.UploadParams, artifactoryUrl string, checksums *targetChecksumsCache) error {
+	cdt.files = nil
+	if cdt.algorithm == ChecksumAlgorithmSha1 && !cdt.checksumOnly {
+		return nil
+	}
+	files, err := collectFilesForUpload(uploadParams, checksums)
+	if err != nil {
+		return err
+	}
+	cdt.files = make(map[string]string, len(files))
+	for _, file := range files {
+		if file.isDir || (file.symlink != "" && uploadParams.IsSymlink()) {
+			continue
+		}
+		targetPath, err := getTargetUrlPath(artifactoryUrl, file.targetPath)
+		if err != nil {
+			return err
+		}
+		cdt.files[targetPath] = file.localPath
+	}
+	return nil
+}
+
+// Returns the local paths of the files, which could not be deployed only by checksum, sorted.
+func (cdt *checksumDeployTransport) takeNotDeployed() []string {
+	cdt.mutex.Lock()
+	defer cdt.mutex.Unlock()
+	notDeployed := cdt.notDeployed
+	cdt.notDeployed = nil
+	sort.Strings(notDeployed)
+	return notDeployed
+}
+
+// Lists the files, which could not be deployed only by checksum by any of the uploaders, so that they can be uploaded in full.
+func logNotChecksumDeployed(uploaders []*specUploader) {
+	var notDeployed []string
+	for _, uploader := range uploaders {
+		notDeployed = append(notDeployed, uploader.transports.checksumDeploy.takeNotDeployed()...)
+	}
+	if len(notDeployed) == 0 {
+		return
+	}
+	sort.Strings(notDeployed)
+	log.Warn(strconv.Itoa(len(notDeployed)), "files could not be deployed by checksum, since Artifactory does not have their content:\n"+strings.Join(notDeployed, "\n"))
+}
+
+// Returns a failed response to the upload request, which is not sent.
+func newChecksumNotFoundResponse(req *http.Request) *http.Response {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return &http.Response{Status: "404 Not Found", StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewReader(nil)), Request: req}
+}
+
+// Returns an error if the upload cannot be done only by checksum: the content read from stdin, archived or extracted
+// while it is uploaded has no local file to deploy by checksum, and the checksum deploy is done by the SHA-256 checksum.
+func validateChecksumOnlyDeploy(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) error {
+	if configuration.ChecksumAlgorithm == ChecksumAlgorithmSha1 {
+		return errorutils.CheckError(errors.New("Deploying only by checksum uses the " + ChecksumAlgorithmSha256 + " checksum algorithm."))
+	}
+	if len(configuration.NoChecksumDeployPatterns) > 0 {
+		return errorutils.CheckError(errors.New("Deploying only by checksum cannot be used with no checksum deploy patterns."))
+	}
+	for i := 0; i < len(uploadSpec.Files); i++ {
+		f := uploadSpec.Get(i)
+		explode, _ := f.IsExplode(false)
+		if f.Pattern == StdinPattern || f.Archive != "" || explode {
+			return errorutils.CheckError(errors.New("File spec entry " + strconv.Itoa(i+1) + ": Deploying only by checksum cannot be used with stdin, archive or explode."))
+		}
+	}
+	return nil
+}
+
+// Returns a copy of the checksum deploy request, with only the specified SHA-256 or SHA-1 checksum.
+func withChecksumHeaders(req *http.Request, sha256, sha1 string) *http.Request {
+	checksumReq := *req
+	checksumReq.Header = make(http.Header, len(req.Header))
+	for name, values := range req.Header {
+		checksumReq.Header[name] = values
+	}
+	checksumReq.Header.Del("X-Checksum")
+	checksumReq.Header.Del("X-Checksum-Sha1")
+	checksumReq.Header.Del("X-Checksum-Sha256")
+	if sha256 != "" {
+		checksumReq.Header.Set("X-Checksum-Sha256", sha256)
+	}
+	if sha1 != "" {
+		checksumReq.Header.Set("X-Checksum-Sha1", sha1)
+	}
+	return &checksumReq
+}
+
+// Returns an error if any of the no checksum deploy patterns is malformed.
+func validateNoChecksumDeployPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errorutils.CheckError(errors.New("Invalid no checksum deploy pattern: " + pattern))
+		}
+	}
+	return nil
+}
+
+// Returns true if the file name matches any of the no checksum deploy patterns.
+func isNoChecksumDeploy(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
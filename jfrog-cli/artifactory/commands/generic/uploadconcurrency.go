@@ -0,0 +1,147 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"strconv"
+	"sync"
+)
+
+// The upload service and its transports, by which spec file entries are uploaded.
+// Each of the entries uploaded in parallel has its own uploader, since the transports hold the state of the entry being uploaded.
+type specUploader struct {
+	uploadService *services.UploadService
+	transports    *uploadTransports
+}
+
+// The outcome of the upload of a single spec file entry.
+type specEntryResult struct {
+	filesInfo     []clientutils.FileInfo
+	failures      []UploadResult
+	fileErrors    []*UploadFileError
+	successCount  int
+	failCount     int
+	skippedCount  int
+	errorOccurred bool
+	// The total size of the files saved to the build info, once the entry was uploaded.
+	flushedBytes int64
+}
+
+// Returns the number of spec file entries to upload in parallel, which is at most the number of entries.
+func getSpecConcurrency(specConcurrency, entries int) int {
+	if specConcurrency > entries {
+		specConcurrency = entries
+	}
+	if specConcurrency <= 1 {
+		return 1
+	}
+	log.Info("Uploading up to", strconv.Itoa(specConcurrency), "spec file entries in parallel.")
+	return specConcurrency
+}
+
+// Creates the uploaders of the spec file entries, one for each of the entries uploaded in parallel.
+// The first uploader holds the state shared by all of the uploaders.
+func createSpecUploaders(servicesConfig artifactory.Config, changedFilter *changedFilesFilter, specConcurrency int, configuration *UploadConfiguration) ([]*specUploader, error) {
+	uploadService, err := createUploadService(servicesConfig, configuration.ArtDetails, configuration)
+	if err != nil {
+		return nil, err
+	}
+	transports, err := wrapUploadTransport(uploadService, changedFilter, configuration)
+	if err != nil {
+		return nil, err
+	}
+	if configuration.Resume {
+		if err = resumeMultipartUploads(transports); err != nil {
+			return nil, err
+		}
+	}
+	uploaders := []*specUploader{{uploadService: uploadService, transports: transports}}
+	for len(uploaders) < specConcurrency {
+		uploader, err := newSpecUploader(servicesConfig, transports, configuration)
+		if err != nil {
+			return nil, err
+		}
+		uploaders = append(uploaders, uploader)
+	}
+	return uploaders, nil
+}
+
+// Creates an additional uploader, with the same configuration as the uploader of the specified transports.
+// The resume state is shared by all of the uploaders, since it is saved to a single file. The retries budget and the
+// rate of the requests are shared, since they limit the upload as a whole.
+func newSpecUploader(servicesConfig artifactory.Config, transports *uploadTransports, configuration *UploadConfiguration) (*specUploader, error) {
+	uploadService, err := createUploadService(servicesConfig, configuration.ArtDetails, configuration)
+	if err != nil {
+		return nil, err
+	}
+	uploaderTransports, err := wrapUploadTransport(uploadService, transports.getChangedFilesFilter(), configuration)
+	if err != nil {
+		return nil, err
+	}
+	if transports.multipart != nil {
+		uploaderTransports.multipart.resume = transports.multipart.resume
+	}
+	if transports.requestRate != nil {
+		uploaderTransports.requestRate.limiter = transports.requestRate.limiter
+	}
+	if budget := transports.getRetriesBudget(); budget != nil {
+		uploaderTransports.retriesLimit.budget = budget
+		if uploaderTransports.multipart != nil {
+			uploaderTransports.multipart.budget = budget
+		}
+	}
+	return &specUploader{uploadService: uploadService, transports: uploaderTransports}, nil
+}
+
+// Uploads the spec file entries by the uploaders, so that up to one entry per uploader is uploaded in parallel.
+// Returns the results in the order of the entries in the spec. If failFast is set, no more entries are started once
+// an entry fails, and the entries which were not uploaded have empty results.
+func uploadSpecEntries(uploadSpec *spec.SpecFiles, uploaders []*specUploader, failFast bool, uploadEntry func(i int, uploader *specUploader) specEntryResult) []specEntryResult {
+	results := make([]specEntryResult, len(uploadSpec.Files))
+	idle := make(chan *specUploader, len(uploaders))
+	for _, uploader := range uploaders {
+		idle <- uploader
+	}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	stopped := false
+	for i := 0; i < len(uploadSpec.Files); i++ {
+		uploader := <-idle
+		mutex.Lock()
+		stop := stopped
+		mutex.Unlock()
+		if stop {
+			log.Error("Stopping the upload, since the", ErrorModeFailFast, "error mode is used.")
+			break
+		}
+		wg.Add(1)
+		go func(i int, uploader *specUploader) {
+			defer wg.Done()
+			result := uploadEntry(i, uploader)
+			mutex.Lock()
+			results[i] = result
+			if failFast && (result.errorOccurred || result.failCount > 0) {
+				stopped = true
+			}
+			mutex.Unlock()
+			idle <- uploader
+		}(i, uploader)
+	}
+	wg.Wait()
+	return results
+}
+
+// Returns a function, which returns true if the target URL path was successfully deployed by checksum by any of the uploaders.
+func isChecksumDeployedByUploaders(uploaders []*specUploader) func(string) bool {
+	return func(targetPath string) bool {
+		for _, uploader := range uploaders {
+			if uploader.transports.status.isChecksumDeployed(targetPath) {
+				return true
+			}
+		}
+		return false
+	}
+}
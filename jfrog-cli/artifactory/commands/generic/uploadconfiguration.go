@@ -0,0 +1,225 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"strings"
+	"time"
+)
+
+type UploadConfiguration struct {
+	Deb                    string
+	Threads                int
+	MinChecksumDeploySize  int64
+	BuildName              string
+	BuildNumber            string
+	DryRun                 bool
+	Symlink                bool
+	ExplodeArchive         bool
+	ArtDetails             *config.ArtifactoryDetails
+	Retries                int
+	SummaryOutput          string
+	SyncDeletes            string
+	Quiet                  bool
+	ProgressInterval       int
+	FailNoOp               bool
+	RetryWaitMilliSecs     int
+	MaxUploadRateKbps      int
+	AddUploadTimestampProp bool
+	DryRunOutput           string
+	FallbackTargets        []string
+	VerifyUpload           bool
+	AddProps               bool
+	// Files larger than ChunkSizeMB are uploaded in parts of that size, SplitCount parts at a time. 0 disables multipart uploads.
+	// Each of the threads uploads its own file in parts, so up to Threads × SplitCount parts are uploaded at a time. The
+	// split count is reduced if this exceeds 64.
+	ChunkSizeMB int
+	SplitCount  int
+	// Resume the interrupted uploads in parts, skipping their parts which were already uploaded.
+	Resume        bool
+	SignArtifacts bool
+	// The path to an ASCII-armored file, containing the private key for signing the uploaded artifacts.
+	SigningKeyPath       string
+	SigningKeyPassphrase string
+	// One of SymlinkValidationStrict, SymlinkValidationLoose or SymlinkValidationOff. Defaults to SymlinkValidationLoose.
+	SymlinkValidation string
+	// Skip the upload of files, which already exist at their target path with the same checksum.
+	SkipExisting bool
+	// Print whether the target of each planned upload is new, would be overwritten or is identical. Applies only to dry runs.
+	PreviewConflicts bool
+	// Print a table of the uploaded artifacts and of the files which failed to upload, at the end of the upload.
+	DetailedSummary bool
+	// The key of the Artifactory project, which the collected build is associated with.
+	Project string
+	// Do not add the build.timestamp property to the uploaded artifacts, when collecting build info.
+	SkipBuildTimestampProp bool
+	// The parts of the Debian configuration, as an alternative to Deb. Either all or none of them should be set.
+	DebDistribution string
+	DebComponent    string
+	DebArchitecture string
+	// If positive, a retry is added to the retries of each file for each RetriesSizeScalingMB of its size, up to MaxRetries.
+	RetriesSizeScalingMB int
+	MaxRetries           int
+	// Shell commands, which are run before and after the upload. The upload is aborted if the pre-upload hook fails.
+	PreUploadHook  string
+	PostUploadHook string
+	// Fail the upload if the post-upload hook fails, rather than only reporting it.
+	FailOnPostUploadHook bool
+	// The time, by which the {year}, {month}, {day} and {epoch} placeholders of the targets are expanded.
+	// If not set, the time the upload starts is used, so that all of the files of the upload get the same date.
+	TargetTime time.Time
+	// One of ErrorModeContinue or ErrorModeFailFast. Defaults to ErrorModeContinue.
+	ErrorMode string
+	// Upload only the files modified after this time, if set.
+	ModifiedAfter time.Time
+	// Upload only the files changed in the git working tree since this git ref, if set. Untracked files are considered changed.
+	ChangedSince string
+	// One of ChecksumAlgorithmSha256 or ChecksumAlgorithmSha1, by which the artifacts are deployed by checksum.
+	// Defaults to ChecksumAlgorithmSha256.
+	ChecksumAlgorithm string
+	// The maximum number of source files, whose content is uploaded simultaneously, regardless of the number of threads.
+	// If 0, it is derived from the open files limit of the process. If negative, it is not limited.
+	MaxOpenFiles int
+	// Attach the git revision and branch of the working directory to the uploaded artifacts.
+	AddVcsProps bool
+	// If positive, the ExpiryProp property is attached to the uploaded artifacts, with the time the upload started plus Expiry.
+	Expiry time.Duration
+	// The path of a JSON template, rendered for each of the uploaded artifacts and uploaded next to it with the
+	// SidecarExtension, if set.
+	SidecarTemplate string
+	// The maximum number of spec file entries uploaded in parallel. Each of the entries is uploaded with its own threads.
+	// If 0 or 1, the entries are uploaded one after the other.
+	SpecConcurrency int
+	// Keep the artifacts in the build info in the order in which they were uploaded, rather than sorting them by their target paths.
+	NoSortArtifacts bool
+	// Skip the verification of the TLS certificate of the Artifactory server by the upload requests. Unsafe, and intended
+	// only for testing. Unlike the details of the server, it is never saved to the stored configuration.
+	InsecureTls bool
+	// Read back the props of the uploaded artifacts, and set the missing props separately from the upload.
+	// The artifacts whose props could not be fully applied are counted as failed, and are not added to the build info.
+	PropsAtomic bool
+	// Upload the content of the files the symlinks point to, to the target paths of the symlinks themselves, with the
+	// SymlinkNameProp property holding the name of the symlink. Symlink cycles fail the upload. Cannot be used with Symlink.
+	FollowSymlinks bool
+	// If positive, the timeout of establishing each connection and of waiting for the response headers of each request.
+	// A request exceeding it fails the upload of its file.
+	ConnectionTimeout time.Duration
+	// If positive, the TCP keep-alive period of the connections, which are then kept open for reuse by all of the threads.
+	KeepAlive time.Duration
+	// The criteria of an AQL items.find query. If set, the files are uploaded only if it matches at least one item in
+	// Artifactory. Otherwise, they are skipped.
+	DeployIf string
+	// The path of a file of key=value props, separated by new lines or semicolons, which are attached to all of the
+	// uploaded artifacts in addition to the props of the spec. Supports # comments and environment variables.
+	TargetPropsFile string
+	// If positive, the upload is refused if the total size of the files to upload exceeds MaxTotalSizeMB.
+	MaxTotalSizeMB int
+	// Do not add the build.name, build.number and build.timestamp properties to the uploaded artifacts, when collecting
+	// build info. The artifacts are still added to the build info.
+	NoBuildProps bool
+	// The local repository, which the files targeting virtual repositories are deployed to, instead of the default
+	// deployment repository of the virtual repositories. Should be a member of the virtual repositories.
+	DeployRepo string
+	// A template such as releases/{channel}/*, which is matched against the local paths of the uploaded files.
+	// The path segments matching the {key} parts of the template are attached to the files as the values of the key props.
+	PathToProps string
+	// If positive, the total number of retries of all of the files, on top of the retries of each file. Once the retries
+	// are exhausted, the failed files are no longer retried, and no more spec file entries are uploaded.
+	MaxTotalRetries int
+	// If set, the events of the uploaded files are sent to the channel, as they are uploaded. The channel should be
+	// drained while uploading, since the upload waits for each event to be received. It is not closed by the upload.
+	Events chan<- UploadEvent
+	// Wildcard patterns, such as *.tar.gz, of the names of the files which are not deployed by checksum, regardless of
+	// their size. These files are always uploaded in full.
+	NoChecksumDeployPatterns []string
+	// Deploy the files only by their SHA-256 checksum, regardless of their size, so that their content is never uploaded.
+	// The files whose content Artifactory does not have fail to upload, and are listed at the end of the upload.
+	ChecksumOnlyDeploy bool
+	// If set, the HTTP statuses of the failed uploads, which are retried up to the retries of each file. The uploads
+	// failing with other statuses fail immediately. Otherwise, the uploads failing with a status of 500 and above are retried.
+	RetryOnStatus []int
+	// A Go text/template, which renders the summary printed at the end of the upload, instead of the default summary.
+	// The template is executed with an UploadSummaryTemplateData, whether or not the upload failed.
+	SummaryTemplate string
+	// Merge the artifacts into the partial build info of their module saved by previous uploads of the same build, rather
+	// than adding a new partial build info, so that parallel uploads of the build accumulate their artifacts.
+	BuildAppend bool
+	// The directory of the temporary files of the upload, such as the files lists of the upload hooks, which are removed
+	// once they are no longer needed. If empty, the temp dir of the CLI is used.
+	TempDir string
+	// Set to true to delete the local files, once they are uploaded. The files which failed to upload are kept.
+	DeleteOnSuccess bool
+	// Calculate the checksums of the local files during a dry run, so that the conflicts preview and the results are
+	// accurate. Reads the content of all of the files, including creating the archives of the spec, which is slow for
+	// large uploads. Otherwise, the checksums which are not already known are left empty by the dry run.
+	DryRunComputeChecksums bool
+	// If positive, the maximum number of requests per second sent by all of the threads, including the requests setting
+	// the props of the uploaded artifacts. The requests exceeding the rate are delayed, regardless of their size.
+	MaxRequestsPerSec int
+	// A target path in the form of <repository name>/<repository path>, to which the uploaded artifacts are copied once
+	// all of them are successfully uploaded, so that it always holds the latest upload. If it ends with a slash, the
+	// artifacts are copied into it by their names. Otherwise, the upload should upload a single artifact.
+	UpdateLatest string
+	// Save the artifacts of each spec file entry to the build info once the entry is fully uploaded, merged into the
+	// partial build info of its module as with BuildAppend, rather than once all of the entries are uploaded. The
+	// details of the saved artifacts are then released, unless they are needed once all of the entries are uploaded,
+	// so that the memory of uploads of many files is bounded. The build info keeps the artifacts of the entries
+	// uploaded before a failure. Cannot be used with VerifyUpload.
+	BuildFlush bool
+	// Upload the files which change between the time they are queued for upload and the time their content is sent.
+	// Otherwise, the size and modification time of each of the files are checked before it is sent and once its content
+	// is read, and the files which changed fail to upload, so that inconsistent content is not deployed.
+	IgnoreFileChanges bool
+	// The directory of a cache of the SHA256 checksums of the local files, which is kept between uploads, so that the
+	// checksums of the files which did not change since a previous upload are not calculated again. A cached checksum
+	// is reused while the size and modification time of its file are unchanged. If empty, no cache is used.
+	ChecksumCacheDir string
+	// Generate a listing of each of the directories of the uploaded artifacts, and of their parent directories up to the
+	// root of their repositories, and upload it into the directory as an index.html file, so that the uploaded
+	// artifacts can be browsed. The listings include only the artifacts uploaded by the current upload, and overwrite
+	// the index files uploaded by previous uploads. The index files are included in the build info, as artifacts of the
+	// module of the first spec file entry. The indexes are generated only if all of the files were uploaded.
+	GenerateIndex bool
+	// Delete all of the artifacts uploaded by the upload if any of the files fails to upload, so that the upload is
+	// all-or-nothing. The upload stops at the first spec file entry which fails, as with the fail-fast error mode. The
+	// deletions are retried as the uploads are, and the artifacts which the rollback failed to delete are reported by
+	// the UploadPartialError. The existence of each target path is checked before its first upload, and the artifacts
+	// which existed before the upload, whether overwritten or skipped, are kept rather than deleted, since their
+	// previous versions cannot be restored. They are reported by the UploadPartialError as not rolled back.
+	// Cannot be used with BuildFlush.
+	Transactional bool
+	// A token appended to the User-Agent of the upload requests, after the base identifier of the CLI, so that the
+	// uploads can be attributed in the access logs of Artifactory. The requests sent by the services manager, such as
+	// the requests setting props or searching for artifacts, keep the base User-Agent. If empty, nothing is appended.
+	UserAgentSuffix string
+	// A target path in the form of <repository name>/<repository path>, to which the partial build infos saved by the
+	// upload are uploaded as a JSON artifact, so that a copy of the build info is archived. If it ends with a slash,
+	// the artifact is named <build name>-<build number>.json. Used only when the build info is collected, and not in a
+	// dry run. Cannot be used with BuildFlush.
+	BuildInfoTarget string
+	// Maps the extensions of the uploaded files to props attached to them, in addition to the props of their spec file
+	// entries and the build props, such as "jar:type=library|war:type=webapp|*:type=other". The entries are separated
+	// by |, and each of them is an extension followed by a colon and its props, in the form of "key1=value1;key2=value2".
+	// The longest extension matching the end of the file name applies. The * extension applies to the other files.
+	ExtProps string
+}
+
+// Returns the Debian configuration, in the form of distribution/component/architecture.
+// If the separate parts of the configuration are set, the configuration is assembled from them, escaping the slashes in their values.
+func getDebConfig(configuration *UploadConfiguration) (string, error) {
+	parts := []string{configuration.DebDistribution, configuration.DebComponent, configuration.DebArchitecture}
+	if strings.Join(parts, "") == "" {
+		return configuration.Deb, nil
+	}
+	if configuration.Deb != "" {
+		return "", errorutils.CheckError(errors.New("The Debian configuration should be set either in the form of distribution/component/architecture, or as separate distribution, component and architecture, but not both."))
+	}
+	for i, name := range []string{"distribution", "component", "architecture"} {
+		if parts[i] == "" {
+			return "", errorutils.CheckError(errors.New("The Debian " + name + " is missing. The distribution, component and architecture should be set together."))
+		}
+		parts[i] = strings.Replace(parts[i], "/", "\\/", -1)
+	}
+	return strings.Join(parts, "/"), nil
+}
@@ -0,0 +1,75 @@
+package generic
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"text/tabwriter"
+)
+
+// The statuses of the targets in the conflicts preview.
+const (
+	// The target does not exist in Artifactory.
+	ConflictNew = "NEW"
+	// The target exists in Artifactory with a different checksum, and would be overwritten.
+	ConflictOverwrite = "OVERWRITE"
+	// The target exists in Artifactory with the same checksum.
+	ConflictIdentical = "IDENTICAL"
+)
+
+// The status of the target of a single planned upload, compared to the existing content in Artifactory.
+type UploadConflict struct {
+	LocalPath  string
+	TargetPath string
+	Status     string
+}
+
+// Compares the targets of the uploads planned by a dry run to the existing content in Artifactory.
+// The local checksums are taken from the details of the planned uploads.
+func previewConflicts(filesInfo []clientutils.FileInfo, artifactoryUrl string, uploadService *services.UploadService) ([]UploadConflict, error) {
+	log.Info("Checking the targets of the planned uploads in Artifactory...")
+	conflicts := make([]UploadConflict, len(filesInfo))
+	for i, fileInfo := range filesInfo {
+		targetPath := getRelativeTargetPath(fileInfo.ArtifactoryPath, artifactoryUrl)
+		info, err := getExistingStorageInfo(targetPath, uploadService)
+		if err != nil {
+			return nil, err
+		}
+		conflict := UploadConflict{LocalPath: fileInfo.LocalPath, TargetPath: targetPath, Status: ConflictNew}
+		if info != nil {
+			conflict.Status = ConflictOverwrite
+			if fileInfo.FileHashes != nil && fileInfo.Sha1 != "" && info.Checksums.Sha1 == fileInfo.Sha1 {
+				conflict.Status = ConflictIdentical
+			}
+		}
+		conflicts[i] = conflict
+	}
+	return conflicts, nil
+}
+
+func printConflictsPreview(filesInfo []clientutils.FileInfo, artifactoryUrl string, uploadService *services.UploadService) error {
+	conflicts, err := previewConflicts(filesInfo, artifactoryUrl, uploadService)
+	if err != nil {
+		return err
+	}
+	return writeConflictsPreview(reportWriter, conflicts)
+}
+
+// Writes an aligned table of the planned uploads and the statuses of their targets.
+func writeConflictsPreview(writer io.Writer, conflicts []UploadConflict) error {
+	counts := make(map[string]int)
+	tw := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tSOURCE\tTARGET")
+	for _, conflict := range conflicts {
+		fmt.Fprintln(tw, conflict.Status+"\t"+conflict.LocalPath+"\t"+conflict.TargetPath)
+		counts[conflict.Status]++
+	}
+	if err := tw.Flush(); errorutils.CheckError(err) != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(writer, "New:", counts[ConflictNew], "Overwrite:", counts[ConflictOverwrite], "Identical:", counts[ConflictIdentical])
+	return errorutils.CheckError(err)
+}
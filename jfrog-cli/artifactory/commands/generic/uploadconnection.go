@@ -0,0 +1,37 @@
+package generic
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// The dial timeout and TCP keep-alive period of the default transport, used when only one of them is configured.
+const (
+	defaultDialTimeout = 30 * time.Second
+	defaultKeepAlive   = 30 * time.Second
+)
+
+// Sets the connection timeout and keep-alive of the upload service's transport. If not set, the defaults of the transport are kept.
+// The connection timeout limits both the establishment of each connection and the wait for the response headers of each
+// request, so that an unresponsive server fails the upload of the file rather than stalling it.
+// When the keep-alive is set, up to one idle connection per thread is kept, so that the connections are reused by the threads.
+func setUploadConnection(transport *http.Transport, connectionTimeout, keepAlive time.Duration, threads int) {
+	if connectionTimeout <= 0 && keepAlive <= 0 {
+		return
+	}
+	dialer := &net.Dialer{Timeout: defaultDialTimeout, KeepAlive: defaultKeepAlive}
+	if connectionTimeout > 0 {
+		dialer.Timeout = connectionTimeout
+		transport.ResponseHeaderTimeout = connectionTimeout
+		transport.TLSHandshakeTimeout = connectionTimeout
+	}
+	if keepAlive > 0 {
+		dialer.KeepAlive = keepAlive
+		transport.IdleConnTimeout = keepAlive
+		if threads > transport.MaxIdleConnsPerHost {
+			transport.MaxIdleConnsPerHost = threads
+		}
+	}
+	transport.DialContext = dialer.DialContext
+}
@@ -0,0 +1,74 @@
+package generic
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// The number of bytes http.DetectContentType considers.
+const contentSniffLen = 512
+
+// An http.RoundTripper, which sets the Content-Type and Content-Encoding headers of the upload requests.
+// The content type is taken from contentType if set. Otherwise, it is detected from the extension of the target path,
+// falling back to sniffing the beginning of the uploaded content.
+// If the content type cannot be detected, the header is not set, leaving the content type to Artifactory.
+type contentTypeTransport struct {
+	transport http.RoundTripper
+	// The content type of the spec file currently being uploaded. Set between the uploads of the spec files.
+	contentType string
+	// The content encoding of the spec file currently being uploaded, such as gzip. Set between the uploads of the spec files.
+	// The files are uploaded as is, so they are expected to be already encoded, and their checksums are of the encoded content.
+	contentEncoding string
+}
+
+func (ct *contentTypeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	targetPath := strings.SplitN(req.URL.Path, ";", 2)[0]
+	if req.Method != http.MethodPut || strings.HasSuffix(targetPath, "/") {
+		return ct.transport.RoundTrip(req)
+	}
+	if ct.contentEncoding != "" {
+		req.Header.Set("Content-Encoding", ct.contentEncoding)
+	}
+	if req.Header.Get("Content-Type") != "" {
+		return ct.transport.RoundTrip(req)
+	}
+	contentType := ct.contentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(targetPath))
+	}
+	// The content of encoded files does not reflect their type, so it is not sniffed.
+	if contentType == "" && ct.contentEncoding == "" && req.Body != nil {
+		var err error
+		if contentType, err = sniffContentType(req); err != nil {
+			return nil, err
+		}
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return ct.transport.RoundTrip(req)
+}
+
+// Detects the content type from the beginning of the request body, which is then restored.
+// Returns an empty string if the content type is unknown.
+func sniffContentType(req *http.Request) (string, error) {
+	buf := make([]byte, contentSniffLen)
+	n, err := io.ReadFull(req.Body, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	req.Body = &multiReadCloser{Reader: io.MultiReader(bytes.NewReader(buf[:n]), req.Body), Closer: req.Body}
+	if contentType := http.DetectContentType(buf[:n]); contentType != "application/octet-stream" {
+		return contentType, nil
+	}
+	return "", nil
+}
+
+type multiReadCloser struct {
+	io.Reader
+	io.Closer
+}
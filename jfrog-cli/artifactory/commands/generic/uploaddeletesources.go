@@ -0,0 +1,65 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"os"
+	"strconv"
+)
+
+// Deletes the local source files of the upload, which Artifactory confirmed.
+// Only the files which were uploaded as is are deleted, so the files of the entries read from stdin, archived or
+// extracted while they are uploaded are kept, as are the files which failed to upload or to be verified.
+// The files which cannot be deleted are only warned about, since they were already uploaded.
+func deleteUploadedSources(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration, results, failures []UploadResult) {
+	sources, err := getUploadedSources(uploadSpec, configuration)
+	if err != nil {
+		log.Warn("Not deleting the uploaded files, since their local paths could not be resolved:", err.Error())
+		return
+	}
+	failed := make(map[string]bool, len(failures))
+	for _, failure := range failures {
+		failed[failure.LocalPath] = true
+	}
+	deleted := 0
+	for _, result := range results {
+		if !sources[result.LocalPath] || failed[result.LocalPath] {
+			continue
+		}
+		// The same file may be uploaded by several spec file entries.
+		delete(sources, result.LocalPath)
+		if err := os.Remove(result.LocalPath); err != nil {
+			log.Warn("Failed deleting the uploaded file", result.LocalPath+":", err.Error())
+			continue
+		}
+		log.Debug("Deleted the uploaded file", result.LocalPath)
+		deleted++
+	}
+	log.Info("Deleted", strconv.Itoa(deleted), "uploaded files.")
+}
+
+// Returns the local files, which are uploaded as is by the upload spec.
+func getUploadedSources(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) (map[string]bool, error) {
+	sources := make(map[string]bool)
+	for i := 0; i < len(uploadSpec.Files); i++ {
+		f := uploadSpec.Get(i)
+		explode, _ := f.IsExplode(false)
+		if f.Pattern == StdinPattern || f.Archive != "" || explode {
+			continue
+		}
+		uploadParams, err := getUploadParams(f, configuration)
+		if err != nil {
+			return nil, err
+		}
+		files, err := collectFilesForUpload(uploadParams, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if !file.isDir {
+				sources[file.localPath] = true
+			}
+		}
+	}
+	return sources, nil
+}
@@ -0,0 +1,52 @@
+package generic
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"strconv"
+)
+
+// Validates the deploy condition, which is the criteria of an AQL items.find query, such as
+// {"repo":"libs-release","name":"latest.txt","@version":{"$lt":"1.2.0"}}.
+func validateDeployCondition(condition string) error {
+	if !json.Valid([]byte(condition)) {
+		return errorutils.CheckError(errors.New("The deploy condition should be the JSON criteria of an AQL items.find query, but got: " + condition))
+	}
+	return nil
+}
+
+// Returns true if the deploy condition matches at least one item in Artifactory.
+func isDeployConditionMet(condition string, servicesManager *artifactory.ArtifactoryServicesManager) (bool, error) {
+	body, err := servicesManager.Aql(`items.find(` + condition + `).include("name").limit(1)`)
+	if err != nil {
+		return false, err
+	}
+	result := new(struct {
+		Results []json.RawMessage `json:"results"`
+	})
+	if err = json.Unmarshal(body, result); errorutils.CheckError(err) != nil {
+		return false, err
+	}
+	return len(result.Results) > 0, nil
+}
+
+// Returns true if the deploy condition matches at least one item in Artifactory, so that the files are uploaded.
+// Otherwise, reports that the files are skipped.
+func checkDeployCondition(condition string, filesCount int, dryRun bool, servicesManager *artifactory.ArtifactoryServicesManager) (bool, error) {
+	met, err := isDeployConditionMet(condition, servicesManager)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case met && dryRun:
+		log.Info("[Dry run] The deploy condition matches, so the files would be uploaded:", condition)
+	case !met && dryRun:
+		log.Info("[Dry run] The deploy condition does not match any artifact, so", strconv.Itoa(filesCount), "files would be skipped:", condition)
+	case !met:
+		log.Info("Skipping the upload of", strconv.Itoa(filesCount), "files, since the deploy condition does not match any artifact:", condition)
+	}
+	return met, nil
+}
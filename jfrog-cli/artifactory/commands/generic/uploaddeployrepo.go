@@ -0,0 +1,87 @@
+package generic
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/http"
+	"strings"
+)
+
+const virtualRepoClass = "virtual"
+
+type repositoryDetails struct {
+	Rclass string `json:"rclass"`
+	// The member repositories of a virtual repository.
+	Repositories []string `json:"repositories"`
+}
+
+// Replaces the repository of the targets in virtual repositories with the deploy repository, so that the files are deployed
+// to it rather than to the default deployment repository of the virtual repository.
+// Returns an error if the deploy repository is not a member of the virtual repository. If the details of the target repository
+// cannot be retrieved, such as when the user lacks the permissions, the target is kept. The targets of the planned uploads
+// of the entries are replaced as well.
+func setDeployRepo(uploadSpec *spec.SpecFiles, plannedUploads []DryRunUpload, deployRepo string, uploadService *services.UploadService) error {
+	repos := make(map[string]*repositoryDetails)
+	for i := 0; i < len(uploadSpec.Files); i++ {
+		file := uploadSpec.Get(i)
+		repo := strings.SplitN(file.Target, "/", 2)[0]
+		details, ok := repos[repo]
+		if !ok {
+			var err error
+			if details, err = getRepositoryDetails(repo, uploadService); err != nil {
+				return err
+			}
+			repos[repo] = details
+			if details == nil {
+				log.Warn("Could not retrieve the details of the repository", repo+", so the files are deployed to it rather than to", deployRepo+".")
+			}
+		}
+		if details == nil || details.Rclass != virtualRepoClass {
+			continue
+		}
+		if len(details.Repositories) > 0 && !isRepositoryMember(deployRepo, details.Repositories) {
+			return errorutils.CheckError(errors.New("The deploy repository " + deployRepo + " is not a member of the virtual repository " + repo + ", whose members are: " + strings.Join(details.Repositories, ", ")))
+		}
+		log.Debug("Deploying the files of the virtual repository", repo, "to", deployRepo+".")
+		file.Target = deployRepo + strings.TrimPrefix(file.Target, repo)
+		for j := range plannedUploads {
+			if plannedUploads[j].entry == i {
+				plannedUploads[j].Target = deployRepo + strings.TrimPrefix(plannedUploads[j].Target, repo)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the details of the repository, or nil if they cannot be retrieved.
+func getRepositoryDetails(repo string, uploadService *services.UploadService) (*repositoryDetails, error) {
+	repoUrl, err := clientutils.BuildArtifactoryUrl(uploadService.ArtDetails.GetUrl(), "api/repositories/"+repo, make(map[string]string))
+	if err != nil {
+		return nil, err
+	}
+	resp, body, _, err := uploadService.GetJfrogHttpClient().SendGet(repoUrl, true, uploadService.ArtDetails.CreateHttpClientDetails())
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Debug("Artifactory response:", resp.Status)
+		return nil, nil
+	}
+	details := new(repositoryDetails)
+	err = json.Unmarshal(body, details)
+	return details, errorutils.CheckError(err)
+}
+
+func isRepositoryMember(repo string, members []string) bool {
+	for _, member := range members {
+		if member == repo {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+package generic
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/utils"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A single upload, planned by a dry run.
+type DryRunUpload struct {
+	Source string              `json:"source"`
+	Target string              `json:"target"`
+	Props  map[string][]string `json:"props,omitempty"`
+	// The index of the spec file entry of the upload.
+	entry int
+	isDir bool
+	// True if the source is a regular local file, whose size is known.
+	isLocalFile bool
+	size        int64
+}
+
+// Returns the uploads planned by the upload spec, with the placeholders in their targets and props resolved.
+// If the filter is set, only the changed files are planned. The checksums of the files are taken from the checksums cache. The entries which fail to resolve are not planned, and
+// the error of the first of them is returned together with the uploads planned for the rest of the entries.
+func planUploads(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration, filter *changedFilesFilter, checksums *targetChecksumsCache) (plannedUploads []DryRunUpload, err error) {
+	// The build props are only added to the spec when the upload is not a dry run.
+	var buildProps string
+	if !configuration.NoBuildProps {
+		buildProps = utils.CreateBuildPropertiesWithoutTimestamp(configuration.BuildName, configuration.BuildNumber)
+	}
+	if buildProps != "" && !configuration.SkipBuildTimestampProp {
+		buildProps += ";build.timestamp=" + strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+	}
+	pathProps, err := parsePathPropsTemplate(configuration.PathToProps)
+	if err != nil {
+		return nil, err
+	}
+	extProps, err := parseExtPropsMapping(configuration.ExtProps)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(uploadSpec.Files); i++ {
+		entryUploads, entryErr := planEntryUploads(uploadSpec.Get(i), i, buildProps, pathProps, extProps, configuration, filter, checksums)
+		if entryErr != nil {
+			if err == nil {
+				err = entryErr
+			}
+			continue
+		}
+		plannedUploads = append(plannedUploads, entryUploads...)
+	}
+	return
+}
+
+// Returns the uploads planned by a single spec file entry, whose index in the spec is i.
+func planEntryUploads(f *spec.File, i int, buildProps string, pathProps *pathPropsTemplate, extProps *extPropsMapping, configuration *UploadConfiguration, filter *changedFilesFilter, checksums *targetChecksumsCache) ([]DryRunUpload, error) {
+	uploadParams, err := getUploadParams(f, configuration)
+	if err != nil {
+		return nil, err
+	}
+	props := uploadParams.GetProps()
+	addProps(&props, buildProps)
+	addProps(&props, getDebianProps(uploadParams.GetDebian()))
+	files, err := getUploadFiles(f, uploadParams, checksums)
+	if err == nil && !isStdinUpload(uploadParams) && f.Archive == "" {
+		files, err = filterChangedFiles(files, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var plannedUploads []DryRunUpload
+	for _, file := range files {
+		fileProps := resolvePlaceholders(props, file.placeholders)
+		addProps(&fileProps, pathProps.getProps(file.localPath))
+		addProps(&fileProps, extProps.getProps(file.localPath))
+		propsMap, err := createPropsMap(fileProps)
+		if err != nil {
+			return nil, err
+		}
+		plannedUpload := DryRunUpload{Source: file.localPath, Target: file.targetPath, Props: propsMap, entry: i, isDir: file.isDir}
+		// Directories, and sources which are not local files, such as stdin, have no size.
+		if stat, err := os.Stat(file.localPath); err == nil && stat.Mode().IsRegular() {
+			plannedUpload.isLocalFile = true
+			plannedUpload.size = stat.Size()
+		}
+		plannedUploads = append(plannedUploads, plannedUpload)
+	}
+	return plannedUploads, nil
+}
+
+// Logs the resolved target and props of each of the planned uploads.
+func logDryRunUploads(plannedUploads []DryRunUpload) {
+	for _, plannedUpload := range plannedUploads {
+		log.Info("[Dry run] Planned upload:", plannedUpload.Source, "->", plannedUpload.Target, "props:", formatPropsMap(plannedUpload.Props))
+	}
+}
+
+// Logs the planned uploads and the folders they upload into, and writes them to configuration.DryRunOutput, if set.
+func reportDryRunUploads(plannedUploads []DryRunUpload, configuration *UploadConfiguration) error {
+	logDryRunUploads(plannedUploads)
+	folders := groupDryRunUploadsByFolder(plannedUploads)
+	logDryRunFolders(folders)
+	if configuration.DryRunOutput == "" {
+		return nil
+	}
+	if err := writeDryRunOutput(configuration.DryRunOutput, plannedUploads); err != nil {
+		return err
+	}
+	return writeDryRunFoldersOutput(getDryRunFoldersOutputPath(configuration.DryRunOutput), folders)
+}
+
+// Writes the planned uploads to the specified path, as newline-delimited JSON.
+func writeDryRunOutput(outputPath string, plannedUploads []DryRunUpload) error {
+	file, err := os.Create(outputPath)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	defer file.Close()
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, plannedUpload := range plannedUploads {
+		if err = errorutils.CheckError(encoder.Encode(plannedUpload)); err != nil {
+			return err
+		}
+	}
+	log.Info("Wrote the planned uploads to:", outputPath)
+	return errorutils.CheckError(writer.Flush())
+}
+
+// The files, which a dry run plans to upload into a single target folder.
+type DryRunFolder struct {
+	Folder string `json:"folder"`
+	Files  int    `json:"files"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// Groups the planned uploads by their target folders, sorted by folder.
+// Only local files are counted, so directories and content read from stdin are skipped.
+func groupDryRunUploadsByFolder(plannedUploads []DryRunUpload) []DryRunFolder {
+	folders := make(map[string]*DryRunFolder)
+	var names []string
+	for _, plannedUpload := range plannedUploads {
+		if !plannedUpload.isLocalFile {
+			continue
+		}
+		name := path.Dir(plannedUpload.Target)
+		folder, ok := folders[name]
+		if !ok {
+			folder = &DryRunFolder{Folder: name}
+			folders[name] = folder
+			names = append(names, name)
+		}
+		folder.Files++
+		folder.Bytes += plannedUpload.size
+	}
+	sort.Strings(names)
+	result := make([]DryRunFolder, 0, len(names))
+	for _, name := range names {
+		result = append(result, *folders[name])
+	}
+	return result
+}
+
+func logDryRunFolders(folders []DryRunFolder) {
+	for _, folder := range folders {
+		log.Info("[Dry run] Target folder:", folder.Folder+"/", "-", strconv.Itoa(folder.Files), "files,", formatBytes(folder.Bytes))
+	}
+}
+
+// Returns the path of the folders summary of the dry run output, such as dry-run.folders.json for dry-run.json.
+func getDryRunFoldersOutputPath(outputPath string) string {
+	ext := filepath.Ext(outputPath)
+	return strings.TrimSuffix(outputPath, ext) + ".folders" + ext
+}
+
+// Writes the target folders to the specified path, as a JSON array.
+func writeDryRunFoldersOutput(outputPath string, folders []DryRunFolder) error {
+	content, err := json.MarshalIndent(folders, "", "  ")
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	if err = errorutils.CheckError(ioutil.WriteFile(outputPath, content, 0644)); err != nil {
+		return err
+	}
+	log.Info("Wrote the target folders summary to:", outputPath)
+	return nil
+}
+
+// Returns the props as a semicolon-separated list, sorted by key.
+func formatPropsMap(propsMap map[string][]string) string {
+	keys := make([]string, 0, len(propsMap))
+	for key := range propsMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var props []string
+	for _, key := range keys {
+		props = append(props, key+"="+strings.Join(propsMap[key], ","))
+	}
+	return strings.Join(props, ";")
+}
+
+func createPropsMap(props string) (map[string][]string, error) {
+	properties, err := clientutils.ParseProperties(props, clientutils.SplitCommas)
+	if err != nil {
+		return nil, err
+	}
+	propsMap := make(map[string][]string)
+	for _, property := range properties.Properties {
+		propsMap[property.Key] = append(propsMap[property.Key], property.Value)
+	}
+	return propsMap, nil
+}
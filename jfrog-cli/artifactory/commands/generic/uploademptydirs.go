@@ -0,0 +1,46 @@
+package generic
+
+import (
+	"bytes"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Uploads an empty placeholder file with the specified name into each of the empty directories matching the upload params,
+// so that the directories are kept by consumers which mirror the repository to a file system.
+// Returns the details of the uploaded placeholders, and the number of placeholders which failed to upload.
+func uploadEmptyDirPlaceholders(placeholderName string, uploadParams services.UploadParams, uploadService *services.UploadService, checksums *targetChecksumsCache) (placeholdersInfo []clientutils.FileInfo, failed int, err error) {
+	files, err := collectFilesForUpload(uploadParams, checksums)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, file := range files {
+		if !file.isDir {
+			continue
+		}
+		entries, err := ioutil.ReadDir(file.localPath)
+		if errorutils.CheckError(err) != nil {
+			return placeholdersInfo, failed, err
+		}
+		if len(entries) > 0 {
+			continue
+		}
+		placeholderPath := filepath.Join(file.localPath, placeholderName)
+		placeholderParams := copyUploadParams(uploadParams)
+		placeholderParams.SetTarget(strings.TrimSuffix(file.targetPath, "/") + "/" + placeholderName)
+		placeholderParams.SetProps(resolvePlaceholders(uploadParams.GetProps(), file.placeholders))
+		placeholderInfo, err := uploadStream(placeholderPath, "the placeholder of the empty directory "+file.localPath, bytes.NewReader(nil), placeholderParams, uploadService)
+		if err != nil {
+			log.Error("Failed uploading the placeholder of the empty directory", file.localPath+":", err)
+			failed++
+			continue
+		}
+		placeholdersInfo = append(placeholdersInfo, placeholderInfo)
+	}
+	return
+}
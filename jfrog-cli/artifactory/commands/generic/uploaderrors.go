@@ -0,0 +1,115 @@
+package generic
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// Returned by Upload when configuration.FailNoOp is set and no artifacts matched the upload spec.
+var ErrNoArtifactsMatched error = &NoMatchError{}
+
+// The error of an upload, in which no artifacts matched the upload spec.
+type NoMatchError struct{}
+
+func (e *NoMatchError) Error() string {
+	return "No artifacts matched the upload spec"
+}
+
+// The failure of a single file to upload.
+type UploadFileError struct {
+	LocalPath  string
+	TargetPath string
+	// The status of the last upload request of the file, or 0 if no response was received.
+	StatusCode int
+	Err        error
+}
+
+func (e *UploadFileError) Error() string {
+	return "Failed uploading " + e.LocalPath + " to " + e.TargetPath + ": " + e.Err.Error()
+}
+
+func (e *UploadFileError) Unwrap() error {
+	return e.Err
+}
+
+// The error of an upload, in which some of the files failed to upload, or in which other errors occurred.
+type UploadPartialError struct {
+	// The number of files which failed to upload.
+	Failed int
+	// The errors of the files which failed to upload, when the files are known. The files of the spec file entries
+	// whose upload stopped on an error, and the signatures, metadata files and placeholders which failed to upload
+	// are only counted.
+	Errors []*UploadFileError
+	// True if an error other than the failures of the files occurred, such as the upload of a spec file entry
+	// stopping on an error, or a failed post-upload hook. Such errors are logged.
+	ErrorOccurred bool
+	// The number of uploaded artifacts, which were deleted by the rollback of a transactional upload.
+	RolledBack int
+	// The target paths of the uploaded artifacts, which the rollback of a transactional upload failed to delete.
+	RollbackFailed []string
+	// The target paths of the uploaded artifacts, which the rollback of a transactional upload kept, since they existed
+	// before the upload, and their previous versions cannot be restored.
+	NotRolledBack []string
+}
+
+func (e *UploadPartialError) Error() string {
+	if len(e.RollbackFailed) > 0 {
+		return "Upload finished with errors, and the rollback failed to delete " + strconv.Itoa(len(e.RollbackFailed)) + " of the uploaded artifacts. Please review the logs"
+	}
+	if len(e.NotRolledBack) > 0 {
+		return "Upload finished with errors, and " + strconv.Itoa(len(e.NotRolledBack)) + " of the uploaded artifacts, which existed before the upload, were not rolled back. Please review the logs"
+	}
+	return "Upload finished with errors. Please review the logs"
+}
+
+// The error of an upload, in which all of the files whose errors are known failed since Artifactory rejected the
+// credentials, or since the user has no permission to deploy to their targets.
+type AuthError struct {
+	// The status of the upload requests, either 401 or 403. If the files failed with both, it is 401.
+	StatusCode int
+	Partial    *UploadPartialError
+}
+
+func (e *AuthError) Error() string {
+	return e.Partial.Error()
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Partial
+}
+
+// Returns the error of an upload with failures. The error is an AuthError if all of the known failures are due to
+// authentication or authorization, and an UploadPartialError otherwise.
+func newUploadError(failed int, fileErrors []*UploadFileError, errorOccurred bool) error {
+	partialErr := &UploadPartialError{Failed: failed, Errors: fileErrors, ErrorOccurred: errorOccurred}
+	statusCode := 0
+	for _, fileError := range fileErrors {
+		if fileError.StatusCode != http.StatusUnauthorized && fileError.StatusCode != http.StatusForbidden {
+			return partialErr
+		}
+		if statusCode != http.StatusUnauthorized {
+			statusCode = fileError.StatusCode
+		}
+	}
+	if statusCode == 0 {
+		return partialErr
+	}
+	return &AuthError{StatusCode: statusCode, Partial: partialErr}
+}
+
+// Returns the errors of the files which failed to upload, by the statuses of their last upload requests.
+func getUploadFileErrors(failures []UploadResult, status *statusTransport, artifactoryUrl string) []*UploadFileError {
+	fileErrors := make([]*UploadFileError, len(failures))
+	for i, failure := range failures {
+		fileError := &UploadFileError{LocalPath: failure.LocalPath, TargetPath: failure.TargetPath, Err: errors.New("No response was received from Artifactory.")}
+		if targetPath, err := getTargetUrlPath(artifactoryUrl, failure.TargetPath); err == nil {
+			fileError.StatusCode = status.getStatus(targetPath)
+		}
+		if fileError.StatusCode != 0 {
+			fileError.Err = errors.New("Artifactory response: " + strconv.Itoa(fileError.StatusCode) + " " + http.StatusText(fileError.StatusCode))
+		}
+		fileErrors[i] = fileError
+	}
+	return fileErrors
+}
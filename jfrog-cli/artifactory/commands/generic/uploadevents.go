@@ -0,0 +1,119 @@
+package generic
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type UploadEventStatus string
+
+const (
+	UploadEventStarted   UploadEventStatus = "started"
+	UploadEventRetried   UploadEventStatus = "retried"
+	UploadEventSucceeded UploadEventStatus = "succeeded"
+	UploadEventFailed    UploadEventStatus = "failed"
+)
+
+// An event of the upload of a single file, sent to the events channel of the upload configuration.
+type UploadEvent struct {
+	// The target path of the file in Artifactory, in the form of <repository>/<path>.
+	Target string
+	Status UploadEventStatus
+	// The size of the uploaded content when the upload succeeded. Zero when the file was deployed by checksum.
+	Bytes int64
+	// The failure of the last attempt, when the upload is retried or failed.
+	Err error
+}
+
+// An http.RoundTripper, which sends the events of the uploaded files to the events channel.
+// Each file is started once, may be retried, and then either succeeds or fails.
+// The events are sent synchronously, so the channel should be drained while uploading.
+type eventsTransport struct {
+	transport      http.RoundTripper
+	events         chan<- UploadEvent
+	artifactoryUrl string
+	// The retries of each file, after which its failure is final.
+	retries int
+	mutex   sync.Mutex
+	// Failed attempts by URL, of the files which were started and did not complete yet.
+	failedAttempts map[string]int
+	lastFailures   map[string]error
+}
+
+func newEventsTransport(transport http.RoundTripper, events chan<- UploadEvent, artifactoryUrl string, retries int) *eventsTransport {
+	return &eventsTransport{
+		transport:      transport,
+		events:         events,
+		artifactoryUrl: artifactoryUrl,
+		retries:        retries,
+		failedAttempts: make(map[string]int),
+		lastFailures:   make(map[string]error),
+	}
+}
+
+// Wraps the transports of the uploaders, so that the events of the uploaded files are sent to configuration.Events.
+func wrapEventsTransports(uploaders []*specUploader, configuration *UploadConfiguration) {
+	for _, uploader := range uploaders {
+		httpClient := uploader.uploadService.GetJfrogHttpClient().Client
+		httpClient.Transport = newEventsTransport(getTransport(httpClient), configuration.Events, uploader.uploadService.ArtDetails.GetUrl(), getUploadRetries(configuration))
+	}
+}
+
+func (et *eventsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Folders created in Artifactory (when uploading with include-dirs) are not files.
+	if req.Method != http.MethodPut || strings.HasSuffix(strings.SplitN(req.URL.Path, ";", 2)[0], "/") {
+		return et.transport.RoundTrip(req)
+	}
+	url := strings.SplitN(req.URL.String(), ";", 2)[0]
+	target := getRelativeTargetPath(url, et.artifactoryUrl)
+	et.mutex.Lock()
+	attempt, started := et.failedAttempts[url]
+	lastFailure := et.lastFailures[url]
+	if !started {
+		et.failedAttempts[url] = 0
+	}
+	et.mutex.Unlock()
+	if !started {
+		et.events <- UploadEvent{Target: target, Status: UploadEventStarted}
+	} else if attempt > 0 {
+		et.events <- UploadEvent{Target: target, Status: UploadEventRetried, Err: lastFailure}
+	}
+
+	resp, err := et.transport.RoundTrip(req)
+	isChecksumDeploy := req.Header.Get("X-Checksum-Deploy") == "true"
+	var event *UploadEvent
+	et.mutex.Lock()
+	switch {
+	case err == nil && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated):
+		event = &UploadEvent{Target: target, Status: UploadEventSucceeded}
+		if !isChecksumDeploy {
+			event.Bytes = req.ContentLength
+		}
+	case isChecksumDeploy:
+		// Checksum deploy requests, which are not satisfied, are followed by a regular upload of the file.
+	case err == nil && resp.StatusCode < 500:
+		// The upload service retries only errors and server failures.
+		event = &UploadEvent{Target: target, Status: UploadEventFailed, Err: errors.New("Artifactory response: " + resp.Status)}
+	default:
+		failure := err
+		if failure == nil {
+			failure = errors.New("Artifactory response: " + resp.Status)
+		}
+		et.failedAttempts[url]++
+		et.lastFailures[url] = failure
+		if et.failedAttempts[url] > et.retries {
+			event = &UploadEvent{Target: target, Status: UploadEventFailed, Err: failure}
+		}
+	}
+	if event != nil {
+		delete(et.failedAttempts, url)
+		delete(et.lastFailures, url)
+	}
+	et.mutex.Unlock()
+	if event != nil {
+		et.events <- *event
+	}
+	return resp, err
+}
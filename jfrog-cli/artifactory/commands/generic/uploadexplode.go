@@ -0,0 +1,140 @@
+package generic
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Extracts the archives matching the upload params locally, and uploads each of their entries under the directory of
+// the target path of the archive, preserving the directory structure of the archive. For example, the entry a/b.txt
+// of repo/dir/bundle.zip is uploaded to repo/dir/a/b.txt.
+// The archives are extracted while their entries are uploaded, so the entries are never stored on disk.
+func uploadExplodedArchives(uploadParams services.UploadParams, uploadService *services.UploadService, checksums *targetChecksumsCache) (artifacts []clientutils.FileInfo, uploaded, failed int, err error) {
+	files, err := collectFilesForUpload(uploadParams, checksums)
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		if file.isDir {
+			continue
+		}
+		entryParams := copyUploadParams(uploadParams)
+		entryParams.ExplodeArchive = false
+		entryParams.SetProps(resolvePlaceholders(uploadParams.GetProps(), file.placeholders))
+		targetDir := path.Dir(file.targetPath)
+		err = walkArchiveEntries(file.localPath, func(name string, content io.Reader) error {
+			entryParams.SetTarget(targetDir + "/" + name)
+			entryInfo, err := uploadStream(file.localPath+"!/"+name, "the entry "+name+" of "+file.localPath, content, entryParams, uploadService)
+			if err != nil {
+				log.Error("Failed uploading the entry", name, "of", file.localPath+":", err)
+				failed++
+				return nil
+			}
+			artifacts = append(artifacts, entryInfo)
+			uploaded++
+			return nil
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Calls the handler with each of the file entries of the archive, according to the extension of the archive.
+func walkArchiveEntries(archivePath string, handler func(name string, content io.Reader) error) error {
+	lowerPath := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(lowerPath, ".zip"):
+		return walkZipEntries(archivePath, handler)
+	case strings.HasSuffix(lowerPath, ".tar.gz") || strings.HasSuffix(lowerPath, ".tgz"):
+		return walkTarEntries(archivePath, true, handler)
+	case strings.HasSuffix(lowerPath, ".tar"):
+		return walkTarEntries(archivePath, false, handler)
+	}
+	return errorutils.CheckError(errors.New("Cannot preserve the structure of " + archivePath + " when exploding it. Only zip, tar and tar.gz archives are supported."))
+}
+
+func walkZipEntries(archivePath string, handler func(name string, content io.Reader) error) error {
+	zipReader, err := zip.OpenReader(archivePath)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	defer zipReader.Close()
+	for _, entry := range zipReader.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		name, err := getArchiveEntryPath(archivePath, entry.Name)
+		if err != nil {
+			return err
+		}
+		content, err := entry.Open()
+		if errorutils.CheckError(err) != nil {
+			return err
+		}
+		err = handler(name, content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkTarEntries(archivePath string, isGzip bool, handler func(name string, content io.Reader) error) error {
+	file, err := os.Open(archivePath)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	defer file.Close()
+	var reader io.Reader = file
+	if isGzip {
+		gzipReader, err := gzip.NewReader(file)
+		if errorutils.CheckError(err) != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if errorutils.CheckError(err) != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, err := getArchiveEntryPath(archivePath, header.Name)
+		if err != nil {
+			return err
+		}
+		if err = handler(name, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+// Returns the clean path of the archive entry, relative to the directory into which the archive is extracted.
+// Entries which would be extracted outside of that directory are rejected.
+func getArchiveEntryPath(archivePath, entryName string) (string, error) {
+	name := path.Clean(strings.Replace(entryName, "\\", "/", -1))
+	if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", errorutils.CheckError(errors.New("The entry " + entryName + " of " + archivePath + " would be extracted outside of the target path."))
+	}
+	return name, nil
+}
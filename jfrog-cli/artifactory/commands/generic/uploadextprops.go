@@ -0,0 +1,73 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"path/filepath"
+	"strings"
+)
+
+// The extension of the ext props mapping, whose props are attached to the files matching none of the other extensions.
+const DefaultExtPropsKey = "*"
+
+// Maps the extensions of the uploaded files to the props attached to them, such as "jar:type=library|war:type=webapp".
+// The entries are separated by |, and each entry is an extension followed by a colon and its props, in the form of
+// "key1=value1;key2=value2". The extensions are matched case-insensitively against the end of the file names, and the
+// longest matching extension applies, so that both gz and tar.gz can be mapped. The * extension applies to the files
+// matching none of the other extensions.
+type extPropsMapping struct {
+	props        map[string]string
+	defaultProps string
+}
+
+// Returns nil if the mapping is empty.
+func parseExtPropsMapping(mapping string) (*extPropsMapping, error) {
+	if strings.TrimSpace(mapping) == "" {
+		return nil, nil
+	}
+	extProps := &extPropsMapping{props: make(map[string]string)}
+	for _, entry := range strings.Split(mapping, "|") {
+		extAndProps := strings.SplitN(entry, ":", 2)
+		ext := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(extAndProps[0]), "."))
+		if len(extAndProps) != 2 || ext == "" || !isValidProps(extAndProps[1]) {
+			return nil, errorutils.CheckError(errors.New("The ext props mapping should contain entries in the form of <extension>:<key>=<value>, separated by |, but got: " + entry))
+		}
+		props := strings.TrimSpace(extAndProps[1])
+		if ext == DefaultExtPropsKey {
+			extProps.defaultProps = props
+			continue
+		}
+		if _, ok := extProps.props[ext]; ok {
+			return nil, errorutils.CheckError(errors.New("The ext props mapping contains the " + ext + " extension more than once"))
+		}
+		extProps.props[ext] = props
+	}
+	return extProps, nil
+}
+
+// Returns true if the props are in the form of "key1=value1;key2=value2".
+func isValidProps(props string) bool {
+	for _, pair := range strings.Split(props, ";") {
+		keyValue := strings.SplitN(pair, "=", 2)
+		if len(keyValue) != 2 || strings.TrimSpace(keyValue[0]) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the props mapped to the extension of the local file, the props of the * extension if none matches, or an
+// empty string if there is no such mapping.
+func (extProps *extPropsMapping) getProps(localPath string) string {
+	if extProps == nil {
+		return ""
+	}
+	name := strings.ToLower(filepath.Base(localPath))
+	props, matched := extProps.defaultProps, ""
+	for ext, extProps := range extProps.props {
+		if len(ext) > len(matched) && strings.HasSuffix(name, "."+ext) {
+			props, matched = extProps, ext
+		}
+	}
+	return props
+}
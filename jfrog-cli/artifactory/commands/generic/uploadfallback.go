@@ -0,0 +1,121 @@
+package generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Returns the target, followed by the target with its repository replaced by each of the fallback repositories.
+func getFallbackTargets(target string, fallbackRepos []string) []string {
+	targets := []string{target}
+	repoPath := ""
+	if i := strings.Index(target, "/"); i >= 0 {
+		repoPath = target[i:]
+	}
+	for _, repo := range fallbackRepos {
+		targets = append(targets, repo+repoPath)
+	}
+	return targets
+}
+
+// An http.RoundTripper, which records the status of the last upload request to each target,
+// the targets which were successfully deployed by checksum, and the paths in which Artifactory stored the targets.
+type statusTransport struct {
+	transport        http.RoundTripper
+	mutex            sync.Mutex
+	statuses         map[string]int
+	checksumDeployed map[string]bool
+	// The paths in the form of <repository>/<path>, in which Artifactory stored the targets, according to the
+	// deploy responses. These may differ from the targets, when the layout of the repository resolves the paths.
+	resolvedPaths map[string]string
+}
+
+// The fields of the deploy response of Artifactory, which hold the path in which the artifact was stored.
+type deployResponse struct {
+	Repo string `json:"repo"`
+	Path string `json:"path"`
+}
+
+func newStatusTransport(transport http.RoundTripper) *statusTransport {
+	return &statusTransport{transport: transport, statuses: make(map[string]int), checksumDeployed: make(map[string]bool), resolvedPaths: make(map[string]string)}
+}
+
+func (st *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := st.transport.RoundTrip(req)
+	if req.Method == http.MethodPut && err == nil {
+		targetPath := strings.SplitN(req.URL.Path, ";", 2)[0]
+		st.mutex.Lock()
+		st.statuses[targetPath] = resp.StatusCode
+		if req.Header.Get("X-Checksum-Deploy") == "true" && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated) {
+			st.checksumDeployed[targetPath] = true
+		}
+		st.mutex.Unlock()
+		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+			st.readResolvedPath(targetPath, resp)
+		}
+	}
+	return resp, err
+}
+
+// Reads the path in which the target was stored from the deploy response, whose body is then restored.
+func (st *statusTransport) readResolvedPath(targetPath string, resp *http.Response) {
+	if resp.Body == nil {
+		return
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	deployed := new(deployResponse)
+	if err != nil || json.Unmarshal(body, deployed) != nil || deployed.Repo == "" || deployed.Path == "" {
+		return
+	}
+	st.mutex.Lock()
+	st.resolvedPaths[targetPath] = deployed.Repo + "/" + strings.TrimPrefix(deployed.Path, "/")
+	st.mutex.Unlock()
+}
+
+// Returns the path in the form of <repository>/<path>, in which Artifactory stored the target URL path,
+// or an empty string if it is unknown.
+func (st *statusTransport) getResolvedPath(targetPath string) string {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	return st.resolvedPaths[targetPath]
+}
+
+// Returns true if the target URL path was successfully deployed by checksum.
+func (st *statusTransport) isChecksumDeployed(targetPath string) bool {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	return st.checksumDeployed[targetPath]
+}
+
+// Returns the status of the last upload request to the target URL path, or 0 if no response was received.
+func (st *statusTransport) getStatus(targetPath string) int {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	return st.statuses[targetPath]
+}
+
+func (st *statusTransport) reset() {
+	st.mutex.Lock()
+	st.statuses = make(map[string]int)
+	st.mutex.Unlock()
+}
+
+// Returns true if all of the recorded upload requests failed with a status, which indicates that the repository is unavailable:
+// 404 for a missing repository, and 503 for a repository under maintenance. A 403 is not a fallback, since it is also
+// returned for rejected credentials and missing deploy permissions, which should fail rather than deploy elsewhere.
+func (st *statusTransport) isRepoUnavailable() bool {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	for _, status := range st.statuses {
+		if status != http.StatusNotFound && status != http.StatusServiceUnavailable {
+			return false
+		}
+	}
+	return len(st.statuses) > 0
+}
@@ -0,0 +1,121 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The size and modification time of a local file, when it was queued for upload.
+type fileSnapshot struct {
+	localPath string
+	size      int64
+	modTime   time.Time
+}
+
+// Returns an error if the local file is no longer the same as when the snapshot was taken.
+func (fs *fileSnapshot) checkUnchanged() error {
+	stat, err := os.Stat(fs.localPath)
+	if err == nil && stat.Size() == fs.size && stat.ModTime().Equal(fs.modTime) {
+		return nil
+	}
+	return errorutils.CheckError(errors.New("File changed during upload: " + fs.localPath))
+}
+
+// An http.RoundTripper, which fails the upload of the files which changed since they were queued for upload, so that
+// inconsistent content is not deployed. The files are checked before their requests are sent, and once their content
+// is fully read, in which case the request is aborted before it completes.
+type fileChangesTransport struct {
+	transport      http.RoundTripper
+	artifactoryUrl string
+	mutex          sync.Mutex
+	// The snapshots of the files of the spec file currently being uploaded, keyed by their target URL paths.
+	snapshots map[string]*fileSnapshot
+	// The local paths of the files which changed during the upload.
+	changed map[string]bool
+}
+
+func (ft *fileChangesTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	snapshot := ft.snapshots[strings.SplitN(req.URL.Path, ";", 2)[0]]
+	if req.Method != http.MethodPut || snapshot == nil {
+		return ft.transport.RoundTrip(req)
+	}
+	if err := snapshot.checkUnchanged(); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		ft.setChanged(snapshot)
+		return nil, err
+	}
+	if req.Body != nil {
+		req.Body = &fileChangesReader{ReadCloser: req.Body, snapshot: snapshot}
+	}
+	resp, err := ft.transport.RoundTrip(req)
+	if err != nil && snapshot.checkUnchanged() != nil {
+		ft.setChanged(snapshot)
+	}
+	return resp, err
+}
+
+func (ft *fileChangesTransport) setChanged(snapshot *fileSnapshot) {
+	ft.mutex.Lock()
+	ft.changed[snapshot.localPath] = true
+	ft.mutex.Unlock()
+}
+
+// Takes the snapshots of the files of the upload params, before they are uploaded.
+func (ft *fileChangesTransport) setUploadParams(uploadParams services.UploadParams, checksums *targetChecksumsCache) error {
+	files, err := collectFilesForUpload(uploadParams, checksums)
+	if err != nil {
+		return err
+	}
+	ft.snapshots = make(map[string]*fileSnapshot)
+	ft.changed = make(map[string]bool)
+	for _, file := range files {
+		// Symlinks are uploaded as empty files, so they have no content to change.
+		if file.isDir || (file.symlink != "" && uploadParams.IsSymlink()) {
+			continue
+		}
+		stat, err := os.Stat(file.localPath)
+		if errorutils.CheckError(err) != nil {
+			return err
+		}
+		targetPath, err := getTargetUrlPath(ft.artifactoryUrl, file.targetPath)
+		if err != nil {
+			return err
+		}
+		ft.snapshots[targetPath] = &fileSnapshot{localPath: file.localPath, size: stat.Size(), modTime: stat.ModTime()}
+	}
+	return nil
+}
+
+// Replaces the errors of the files which failed to upload since they changed during the upload.
+func (ft *fileChangesTransport) setFileErrors(fileErrors []*UploadFileError) {
+	for _, fileError := range fileErrors {
+		if ft.changed[fileError.LocalPath] {
+			fileError.Err = errors.New("File changed during upload: " + fileError.LocalPath)
+		}
+	}
+}
+
+// Fails the read of the content of a file, once it is fully read, if the file changed since it was queued for upload.
+type fileChangesReader struct {
+	io.ReadCloser
+	snapshot *fileSnapshot
+}
+
+func (fr *fileChangesReader) Read(p []byte) (n int, err error) {
+	n, err = fr.ReadCloser.Read(p)
+	if err == io.EOF {
+		if changedErr := fr.snapshot.checkUnchanged(); changedErr != nil {
+			return n, changedErr
+		}
+	}
+	return
+}
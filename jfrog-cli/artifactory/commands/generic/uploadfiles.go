@@ -0,0 +1,159 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/fspatterns"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A local file or directory, matching the pattern of an upload spec file.
+type uploadFile struct {
+	localPath  string
+	targetPath string
+	symlink    string
+	isDir      bool
+	// The values captured by the groups of the pattern, which replace the {1}, {2}... placeholders.
+	placeholders []string
+}
+
+// Returns the local files and directories, which would be uploaded by the upload service for the provided upload params.
+// The files are resolved exactly as the upload service resolves them, so that the returned list can be used to plan and
+// report on the upload, before it is performed. The checksums of the files, whose targets have checksum placeholders,
+// are taken from the checksums cache.
+func collectFilesForUpload(uploadParams services.UploadParams, checksums *targetChecksumsCache) ([]uploadFile, error) {
+	uploadParams = copyUploadParams(uploadParams)
+	if strings.Index(uploadParams.GetTarget(), "/") < 0 {
+		uploadParams.SetTarget(uploadParams.GetTarget() + "/")
+	}
+	uploadParams.SetPattern(utils.ReplaceTildeWithUserHome(uploadParams.GetPattern()))
+	rootPath, err := fspatterns.GetRootPath(uploadParams.GetPattern(), uploadParams.IsRegexp(), uploadParams.IsSymlink())
+	if err != nil {
+		return nil, err
+	}
+	isDir, err := fileutils.IsDirExists(rootPath, uploadParams.IsSymlink())
+	if err != nil {
+		return nil, err
+	}
+
+	// A single file (or a symlink while preserving symlinks).
+	if !isDir || (fileutils.IsPathSymlink(rootPath) && uploadParams.IsSymlink()) {
+		artifact, err := fspatterns.GetSingleFileToUpload(rootPath, uploadParams.GetTarget(), uploadParams.IsFlat(), uploadParams.IsSymlink())
+		if err != nil {
+			return nil, err
+		}
+		files := []uploadFile{{localPath: artifact.LocalPath, targetPath: artifact.TargetPath, symlink: artifact.Symlink}}
+		return files, expandFilesTargetChecksums(files, checksums)
+	}
+
+	uploadParams.SetPattern(utils.PrepareLocalPathForUpload(uploadParams.GetPattern(), uploadParams.IsRegexp()))
+	excludePathPattern := fspatterns.PrepareExcludePathPattern(uploadParams)
+	patternRegex, err := regexp.Compile(uploadParams.GetPattern())
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	paths, err := fspatterns.GetPaths(rootPath, uploadParams.IsRecursive(), uploadParams.IsIncludeDirs(), uploadParams.IsSymlink())
+	if err != nil {
+		return nil, err
+	}
+	// Longest paths first
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	var files []uploadFile
+	var foldersPaths []string
+	for index, path := range paths {
+		matches, isDir, isSymlinkFlow, err := fspatterns.PrepareAndFilterPaths(path, excludePathPattern, uploadParams.IsSymlink(), uploadParams.IsIncludeDirs(), patternRegex)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		tempPaths := paths
+		tempIndex := index
+		if uploadParams.IsFlat() && uploadParams.IsIncludeDirs() && isDir {
+			foldersPaths = append(foldersPaths, path)
+			tempPaths = foldersPaths
+			tempIndex = len(foldersPaths) - 1
+		}
+		file, include, err := createUploadFile(path, uploadParams, matches, isDir, isSymlinkFlow, tempPaths, tempIndex)
+		if err != nil {
+			return nil, err
+		}
+		if include {
+			files = append(files, file)
+		}
+	}
+	return files, expandFilesTargetChecksums(files, checksums)
+}
+
+// Returns the files, which would be uploaded for the file spec. Uploads from stdin and uploads of archives created from
+// the matched files are returned as a single file, uploaded to the exact target path.
+func getUploadFiles(f *spec.File, uploadParams services.UploadParams, checksums *targetChecksumsCache) ([]uploadFile, error) {
+	if isStdinUpload(uploadParams) {
+		return []uploadFile{{localPath: StdinPattern, targetPath: uploadParams.GetTarget()}}, nil
+	}
+	files, err := collectFilesForUpload(uploadParams, checksums)
+	if err != nil || f.Archive == "" || len(files) == 0 {
+		return files, err
+	}
+	return []uploadFile{{localPath: uploadParams.GetPattern(), targetPath: uploadParams.GetTarget()}}, nil
+}
+
+// Returns a copy of the upload params, which can be modified without affecting the original params.
+// The upload service modifies the params it receives.
+func copyUploadParams(uploadParams services.UploadParams) services.UploadParams {
+	commonParams := *uploadParams.ArtifactoryCommonParams
+	uploadParams.ArtifactoryCommonParams = &commonParams
+	return uploadParams
+}
+
+func createUploadFile(path string, uploadParams services.UploadParams, groups []string, isDir, isSymlinkFlow bool, paths []string, index int) (uploadFile, bool, error) {
+	placeholders := groups[1:]
+	target := resolvePlaceholders(uploadParams.GetTarget(), placeholders)
+	symlinkPath, err := fspatterns.GetFileSymlinkPath(path)
+	if err != nil {
+		return uploadFile{}, false, err
+	}
+	if uploadParams.IsSymlink() || symlinkPath == "" {
+		target = getUploadTarget(path, target, uploadParams.IsFlat())
+	} else {
+		target = getUploadTarget(symlinkPath, target, uploadParams.IsFlat())
+	}
+	file := uploadFile{localPath: path, targetPath: target, symlink: symlinkPath, placeholders: placeholders}
+	if isDir && uploadParams.IsIncludeDirs() && !isSymlinkFlow {
+		if path == "." || (index != 0 && clientutils.IsSubPath(paths, index, fileutils.GetFileSeparator())) {
+			return uploadFile{}, false, nil
+		}
+		file.isDir = true
+	}
+	return file, true, nil
+}
+
+// Replaces the {1}, {2}... placeholders in the value with the corresponding captured values.
+func resolvePlaceholders(value string, placeholders []string) string {
+	for i, placeholder := range placeholders {
+		placeholder = strings.Replace(placeholder, "\\", "/", -1)
+		value = strings.Replace(value, "{"+strconv.Itoa(i+1)+"}", placeholder, -1)
+	}
+	return value
+}
+
+// Construct the target path while taking the 'flat' option into account.
+func getUploadTarget(rootPath, target string, isFlat bool) string {
+	if strings.HasSuffix(target, "/") {
+		if isFlat {
+			fileName, _ := fileutils.GetFileAndDirFromPath(rootPath)
+			target += fileName
+		} else {
+			target += utils.TrimPath(rootPath)
+		}
+	}
+	return target
+}
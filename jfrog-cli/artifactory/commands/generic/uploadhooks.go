@@ -0,0 +1,144 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/cliutils"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// The environment variables, which are exposed to the upload hooks.
+const (
+	HookBuildNameEnv   = "JFROG_CLI_BUILD_NAME"
+	HookBuildNumberEnv = "JFROG_CLI_BUILD_NUMBER"
+	// The path of a file, which lists the uploaded files, one per line, as the local path and the target path separated by a tab.
+	HookFilesEnv = "JFROG_CLI_UPLOAD_FILES"
+	// The number of files, which failed to upload. Exposed only to the post-upload hook.
+	HookFailedEnv = "JFROG_CLI_UPLOAD_FAILED"
+)
+
+// A shell command, which is run before or after the upload.
+type uploadHookCmd struct {
+	command string
+	env     map[string]string
+}
+
+func (hook *uploadHookCmd) GetCmd() *exec.Cmd {
+	if cliutils.IsWindows() {
+		return exec.Command("cmd", "/C", hook.command)
+	}
+	return exec.Command("sh", "-c", hook.command)
+}
+
+func (hook *uploadHookCmd) GetEnv() map[string]string {
+	return hook.env
+}
+
+func (hook *uploadHookCmd) GetStdWriter() io.WriteCloser {
+	return nil
+}
+
+func (hook *uploadHookCmd) GetErrWriter() io.WriteCloser {
+	return nil
+}
+
+// Runs the pre-upload hook with the uploads planned by the upload spec. Returns an error if the hook fails,
+// so that the upload is aborted.
+func runPreUploadHook(plannedUploads []DryRunUpload, configuration *UploadConfiguration) error {
+	files := make([][2]string, len(plannedUploads))
+	for i, plannedUpload := range plannedUploads {
+		files[i] = [2]string{plannedUpload.Source, plannedUpload.Target}
+	}
+	log.Info("Running the pre-upload hook...")
+	if err := runUploadHook(configuration.PreUploadHook, files, nil, configuration); err != nil {
+		return errorutils.CheckError(errors.New("The pre-upload hook failed, so the upload was aborted: " + err.Error()))
+	}
+	return nil
+}
+
+// Runs the post-upload hook with the uploaded files, and the number of files which failed to upload.
+func runPostUploadHook(filesInfo []clientutils.FileInfo, failCount int, configuration *UploadConfiguration) error {
+	files := make([][2]string, len(filesInfo))
+	for i, fileInfo := range filesInfo {
+		files[i] = [2]string{fileInfo.LocalPath, getRelativeTargetPath(fileInfo.ArtifactoryPath, configuration.ArtDetails.Url)}
+	}
+	log.Info("Running the post-upload hook...")
+	if err := runUploadHook(configuration.PostUploadHook, files, map[string]string{HookFailedEnv: strconv.Itoa(failCount)}, configuration); err != nil {
+		return errorutils.CheckError(errors.New("The post-upload hook failed: " + err.Error()))
+	}
+	return nil
+}
+
+// Runs the post-upload hook, and reports its failure. Returns true if the hook failed and
+// configuration.FailOnPostUploadHook is set, so that the upload fails.
+func runAndReportPostUploadHook(filesInfo []clientutils.FileInfo, failCount int, configuration *UploadConfiguration) bool {
+	hookErr := runPostUploadHook(filesInfo, failCount, configuration)
+	if hookErr == nil {
+		return false
+	}
+	if configuration.FailOnPostUploadHook {
+		log.Error(hookErr)
+		return true
+	}
+	log.Warn(hookErr.Error())
+	return false
+}
+
+func runUploadHook(command string, files [][2]string, env map[string]string, configuration *UploadConfiguration) error {
+	filesList, err := writeHookFilesList(files, getUploadTempDir(configuration))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(filesList)
+	hookEnv := map[string]string{HookBuildNameEnv: configuration.BuildName, HookBuildNumberEnv: configuration.BuildNumber, HookFilesEnv: filesList}
+	for key, value := range env {
+		hookEnv[key] = value
+	}
+	return utils.RunCmd(&uploadHookCmd{command: command, env: hookEnv})
+}
+
+func writeHookFilesList(files [][2]string, tempDir string) (string, error) {
+	file, err := ioutil.TempFile(tempDir, "upload-files")
+	if errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	defer file.Close()
+	for _, pair := range files {
+		if _, err = file.WriteString(pair[0] + "\t" + pair[1] + "\n"); errorutils.CheckError(err) != nil {
+			return "", err
+		}
+	}
+	return file.Name(), nil
+}
+
+// Returns the directory of the temporary files of the upload: the temp dir of the configuration, or the temp dir of the
+// CLI, as set by the JFROG_CLI_TEMP_DIR environment variable.
+func getUploadTempDir(configuration *UploadConfiguration) string {
+	if configuration.TempDir != "" {
+		return configuration.TempDir
+	}
+	return cliutils.GetTempDir()
+}
+
+// Returns an error if the temp dir of the configuration is set, but is not an existing directory.
+func validateUploadTempDir(tempDir string) error {
+	if tempDir == "" {
+		return nil
+	}
+	exists, err := fileutils.IsDirExists(tempDir, false)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errorutils.CheckError(errors.New("The temp dir does not exist: " + tempDir))
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package generic
+
+import (
+	"bytes"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"html/template"
+	"path"
+	"sort"
+	"strings"
+)
+
+// The name of the directory listings generated by the upload, when configuration.GenerateIndex is set.
+const IndexFileName = "index.html"
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Index of {{.Path}}/</title>
+</head>
+<body>
+<h1>Index of {{.Path}}/</h1>
+<ul>
+{{- if .HasParent}}
+<li><a href="../{{.IndexFileName}}">../</a></li>
+{{- end}}
+{{- range .Dirs}}
+<li><a href="{{.}}/{{$.IndexFileName}}">{{.}}/</a></li>
+{{- end}}
+{{- range .Files}}
+<li><a href="{{.}}">{{.}}</a></li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+// The listing of a directory in Artifactory, with the names of the artifacts and of the subdirectories uploaded into it.
+type directoryIndex struct {
+	// The path of the directory, in the form of <repository name>/<repository path>, without a trailing slash.
+	Path          string
+	HasParent     bool
+	IndexFileName string
+	Dirs          []string
+	Files         []string
+}
+
+// Returns the listings of the directories of the uploaded artifacts, and of their parent directories up to the root of
+// their repositories, sorted by their paths. The listings include only the artifacts uploaded by the current upload.
+// The directories into which an index file was uploaded are not listed, so that the uploaded index file is kept.
+func createDirectoryIndexes(filesInfo []clientutils.FileInfo, artifactoryUrl string) []*directoryIndex {
+	indexes := make(map[string]*directoryIndex)
+	uploadedIndexes := make(map[string]bool)
+	getIndex := func(dir string) *directoryIndex {
+		index, ok := indexes[dir]
+		if !ok {
+			index = &directoryIndex{Path: dir, HasParent: strings.Contains(dir, "/"), IndexFileName: IndexFileName}
+			indexes[dir] = index
+		}
+		return index
+	}
+	for _, fileInfo := range filesInfo {
+		targetPath := getRelativeTargetPath(fileInfo.ArtifactoryPath, artifactoryUrl)
+		dir, name := path.Split(targetPath)
+		dir = strings.TrimSuffix(dir, "/")
+		if dir == "" {
+			continue
+		}
+		if name == IndexFileName {
+			uploadedIndexes[dir] = true
+		}
+		getIndex(dir).Files = appendUnique(getIndex(dir).Files, name)
+		for strings.Contains(dir, "/") {
+			parent, subdir := path.Split(dir)
+			parent = strings.TrimSuffix(parent, "/")
+			getIndex(parent).Dirs = appendUnique(getIndex(parent).Dirs, subdir)
+			dir = parent
+		}
+	}
+	var sortedIndexes []*directoryIndex
+	for dir, index := range indexes {
+		if uploadedIndexes[dir] {
+			log.Info("Not generating the index of", dir+", since an", IndexFileName, "file was uploaded into it.")
+			continue
+		}
+		sort.Strings(index.Dirs)
+		sort.Strings(index.Files)
+		sortedIndexes = append(sortedIndexes, index)
+	}
+	sort.Slice(sortedIndexes, func(i, j int) bool {
+		return sortedIndexes[i].Path < sortedIndexes[j].Path
+	})
+	return sortedIndexes
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func renderDirectoryIndex(index *directoryIndex) ([]byte, error) {
+	var content bytes.Buffer
+	if err := indexTemplate.Execute(&content, index); errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
+
+// Generates the listings of the directories of the uploaded artifacts, and uploads them as index files into the
+// directories, overwriting the index files generated by previous uploads. The index files are uploaded with the props.
+// Returns the details of the uploaded index files, and the number of index files which failed to upload.
+func uploadDirectoryIndexes(filesInfo []clientutils.FileInfo, props string, uploadService *services.UploadService) (indexesInfo []clientutils.FileInfo, failed int) {
+	for _, index := range createDirectoryIndexes(filesInfo, uploadService.ArtDetails.GetUrl()) {
+		content, err := renderDirectoryIndex(index)
+		if err != nil {
+			log.Error("Failed generating the index of", index.Path+":", err)
+			failed++
+			continue
+		}
+		indexParams := services.NewUploadParams()
+		indexParams.ArtifactoryCommonParams = &clientutils.ArtifactoryCommonParams{Target: index.Path + "/" + IndexFileName, Props: props}
+		// The index files have no local path, since they are generated in memory.
+		indexInfo, err := uploadStream("", "the index of "+index.Path, bytes.NewReader(content), indexParams, uploadService)
+		if err != nil {
+			log.Error("Failed uploading the index of", index.Path+":", err)
+			failed++
+			continue
+		}
+		indexesInfo = append(indexesInfo, indexInfo)
+	}
+	return
+}
+
+// Same as uploadDirectoryIndexes, but the index files are uploaded with the build props, when the build info is collected.
+func uploadIndexes(filesInfo []clientutils.FileInfo, isCollectBuildInfo bool, configuration *UploadConfiguration, uploadService *services.UploadService) (indexesInfo []clientutils.FileInfo, failed int, err error) {
+	indexProps := ""
+	if isCollectBuildInfo && !configuration.NoBuildProps {
+		if err = addBuildProps(&indexProps, configuration.BuildName, configuration.BuildNumber, configuration.SkipBuildTimestampProp); err != nil {
+			return
+		}
+	}
+	indexesInfo, failed = uploadDirectoryIndexes(filesInfo, indexProps, uploadService)
+	return
+}
@@ -0,0 +1,55 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Copies the uploaded artifacts to the latest path, so that it holds the artifacts of the latest upload.
+// If the latest path ends with a slash, each of the artifacts is copied into it by its name. Otherwise, the upload
+// should have uploaded a single artifact, which is copied to the latest path. The existing artifacts are overwritten.
+func updateLatest(latestPath string, filesInfo []clientutils.FileInfo, dryRun bool, uploadService *services.UploadService) error {
+	isFolder := strings.HasSuffix(latestPath, "/")
+	if !isFolder && len(filesInfo) != 1 {
+		return errorutils.CheckError(errors.New("The latest path " + latestPath + " should end with a slash, since " + strconv.Itoa(len(filesInfo)) + " artifacts were uploaded."))
+	}
+	for _, fileInfo := range filesInfo {
+		sourcePath := getRelativeTargetPath(fileInfo.ArtifactoryPath, uploadService.ArtDetails.GetUrl())
+		targetPath := latestPath
+		if isFolder {
+			targetPath += path.Base(sourcePath)
+		}
+		if dryRun {
+			log.Info("[Dry run] Copying", sourcePath, "to the latest path:", targetPath)
+			continue
+		}
+		log.Info("Copying", sourcePath, "to the latest path:", targetPath)
+		if err := copyArtifact(sourcePath, targetPath, uploadService); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copies the artifact in the source path to the target path in Artifactory, overwriting the existing artifact.
+func copyArtifact(sourcePath, targetPath string, uploadService *services.UploadService) error {
+	copyUrl, err := clientutils.BuildArtifactoryUrl(uploadService.ArtDetails.GetUrl(), "api/copy/"+sourcePath, map[string]string{"to": "/" + targetPath})
+	if err != nil {
+		return err
+	}
+	resp, _, err := uploadService.GetJfrogHttpClient().SendPost(copyUrl, nil, uploadService.ArtDetails.CreateHttpClientDetails())
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errorutils.CheckError(errors.New("Failed copying " + sourcePath + " to " + targetPath + ". Artifactory response: " + resp.Status))
+	}
+	return nil
+}
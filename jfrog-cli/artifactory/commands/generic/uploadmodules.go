@@ -0,0 +1,201 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"sort"
+	"strconv"
+)
+
+// The files uploaded by the spec files of a single build info module.
+type uploadModule struct {
+	// The module id. If empty, the build name is used as the module id when the build info is published.
+	id           string
+	artifacts    []clientutils.FileInfo
+	dependencies []clientutils.FileInfo
+}
+
+// Groups the uploaded files by the build info modules of the spec files, which uploaded them, in the order of the spec files.
+// specStarts holds the index in filesInfo of the first file uploaded by each of the spec files.
+// The files of the spec files with the asDependency option are also added to the dependencies of their modules.
+func groupByModule(uploadSpec *spec.SpecFiles, filesInfo []clientutils.FileInfo, specStarts []int) []*uploadModule {
+	var modules []*uploadModule
+	modulesById := make(map[string]*uploadModule)
+	for i, start := range specStarts {
+		end := len(filesInfo)
+		if i+1 < len(specStarts) {
+			end = specStarts[i+1]
+		}
+		f := uploadSpec.Get(i)
+		module, ok := modulesById[f.Module]
+		if !ok {
+			module = &uploadModule{id: f.Module}
+			modulesById[f.Module] = module
+			modules = append(modules, module)
+		}
+		module.artifacts = append(module.artifacts, filesInfo[start:end]...)
+		if asDependency, _ := f.IsAsDependency(false); asDependency {
+			module.dependencies = append(module.dependencies, filesInfo[start:end]...)
+		}
+	}
+	return modules
+}
+
+// Saves the artifacts of a fully uploaded spec file entry to the build info, merged into the partial build info of the
+// module of the entry. The details of the artifacts are then released, unless they are needed once all of the entries
+// are uploaded. The entries which failed are not saved, since the build info is not saved when the upload fails.
+func flushSpecEntryBuildInfo(f *spec.File, result *specEntryResult, configuration *UploadConfiguration) {
+	if result.errorOccurred || result.failCount > 0 || len(result.filesInfo) == 0 {
+		return
+	}
+	// The artifacts are copied, since they are sorted when they are saved.
+	module := &uploadModule{id: f.Module, artifacts: append([]clientutils.FileInfo(nil), result.filesInfo...)}
+	if asDependency, _ := f.IsAsDependency(false); asDependency {
+		module.dependencies = append([]clientutils.FileInfo(nil), result.filesInfo...)
+	}
+	if _, err := saveUploadBuildInfo([]*uploadModule{module}, nil, configuration.BuildName, configuration.BuildNumber, !configuration.NoSortArtifacts, true); err != nil {
+		result.errorOccurred = true
+		log.Error(createUploadErrorRecord(err, f))
+		return
+	}
+	log.Debug("Saved", strconv.Itoa(len(result.filesInfo)), "artifacts of", f.Pattern, "to the build info.")
+	result.flushedBytes = getUploadedBytes(result.filesInfo)
+	if !isFilesInfoRetained(configuration) {
+		result.filesInfo = nil
+	}
+}
+
+// Returns true if the details of all of the uploaded artifacts are needed once all of the spec file entries are
+// uploaded, so that they are kept even when the build info is flushed after each of the entries.
+func isFilesInfoRetained(configuration *UploadConfiguration) bool {
+	return configuration.PostUploadHook != "" || configuration.PreviewConflicts || configuration.SyncDeletes != "" ||
+		configuration.UpdateLatest != "" || configuration.DeleteOnSuccess || configuration.SummaryOutput != "" ||
+		configuration.GenerateIndex || isFailuresCollected(configuration)
+}
+
+// Saves the artifacts and the dependencies of each of the modules as partial build infos.
+// The artifacts and the dependencies are saved as separate partials, since the build info is published with
+// a single kind of data from each partial. The upload stats are saved with the artifacts of the first module.
+// If sortArtifacts is set, the artifacts and the dependencies of each module are sorted by sortByTargetPath.
+// If appendMode is set, they are merged into the partials of the modules saved by previous uploads of the build.
+// Returns the saved partials.
+func saveUploadBuildInfo(modules []*uploadModule, uploadStats buildinfo.Env, buildName, buildNumber string, sortArtifacts, appendMode bool) (partials []*buildinfo.Partial, err error) {
+	if len(modules) == 0 {
+		modules = []*uploadModule{{}}
+	}
+	for i, module := range modules {
+		if sortArtifacts {
+			sortByTargetPath(module.artifacts)
+			sortByTargetPath(module.dependencies)
+		}
+		buildArtifacts := convertFileInfoToBuildArtifacts(module.artifacts)
+		populateFunc := func(partial *buildinfo.Partial) {
+			partial.ModuleId = module.id
+			partial.Artifacts = appendBuildArtifacts(partial.Artifacts, buildArtifacts)
+			if i == 0 {
+				partial.Env = appendBuildEnv(partial.Env, uploadStats)
+			}
+			partials = append(partials, partial)
+		}
+		isAppendable := func(partial *buildinfo.Partial) bool {
+			return partial.ModuleId == module.id && partial.Artifacts != nil
+		}
+		if err = saveUploadPartial(buildName, buildNumber, appendMode, isAppendable, populateFunc); err != nil {
+			return
+		}
+		if len(module.dependencies) == 0 {
+			continue
+		}
+		buildDependencies := convertFileInfoToBuildDependencies(module.dependencies)
+		populateFunc = func(partial *buildinfo.Partial) {
+			partial.ModuleId = module.id
+			partial.Dependencies = appendBuildDependencies(partial.Dependencies, buildDependencies)
+			partials = append(partials, partial)
+		}
+		isAppendable = func(partial *buildinfo.Partial) bool {
+			return partial.ModuleId == module.id && partial.Dependencies != nil
+		}
+		if err = saveUploadPartial(buildName, buildNumber, appendMode, isAppendable, populateFunc); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func saveUploadPartial(buildName, buildNumber string, appendMode bool, isAppendable func(partial *buildinfo.Partial) bool, populateFunc func(partial *buildinfo.Partial)) error {
+	if appendMode {
+		return utils.AppendPartialBuildInfo(buildName, buildNumber, isAppendable, populateFunc)
+	}
+	return utils.SavePartialBuildInfo(buildName, buildNumber, populateFunc)
+}
+
+// Returns the existing artifacts, followed by the added artifacts which do not already exist with the same name and checksum.
+func appendBuildArtifacts(existing, added []buildinfo.Artifact) []buildinfo.Artifact {
+	keys := make(map[string]bool, len(existing))
+	for _, artifact := range existing {
+		keys[artifact.Name+"\x00"+getBuildSha1(artifact.Checksum)] = true
+	}
+	for _, artifact := range added {
+		key := artifact.Name + "\x00" + getBuildSha1(artifact.Checksum)
+		if !keys[key] {
+			keys[key] = true
+			existing = append(existing, artifact)
+		}
+	}
+	return existing
+}
+
+// Returns the existing dependencies, followed by the added dependencies which do not already exist with the same id and checksum.
+func appendBuildDependencies(existing, added []buildinfo.Dependency) []buildinfo.Dependency {
+	keys := make(map[string]bool, len(existing))
+	for _, dependency := range existing {
+		keys[dependency.Id+"\x00"+getBuildSha1(dependency.Checksum)] = true
+	}
+	for _, dependency := range added {
+		key := dependency.Id + "\x00" + getBuildSha1(dependency.Checksum)
+		if !keys[key] {
+			keys[key] = true
+			existing = append(existing, dependency)
+		}
+	}
+	return existing
+}
+
+// Returns the existing env, with the added env. The added values replace the existing values of the same keys.
+func appendBuildEnv(existing, added buildinfo.Env) buildinfo.Env {
+	if existing == nil {
+		return added
+	}
+	for key, value := range added {
+		existing[key] = value
+	}
+	return existing
+}
+
+func getBuildSha1(checksum *buildinfo.Checksum) string {
+	if checksum == nil {
+		return ""
+	}
+	return checksum.Sha1
+}
+
+// Sorts the files by their target paths, and then by their checksums, so that the build info does not depend on the
+// order in which the files were uploaded.
+func sortByTargetPath(filesInfo []clientutils.FileInfo) {
+	sort.SliceStable(filesInfo, func(i, j int) bool {
+		if filesInfo[i].ArtifactoryPath != filesInfo[j].ArtifactoryPath {
+			return filesInfo[i].ArtifactoryPath < filesInfo[j].ArtifactoryPath
+		}
+		return getSha1(filesInfo[i]) < getSha1(filesInfo[j])
+	})
+}
+
+func getSha1(fileInfo clientutils.FileInfo) string {
+	if fileInfo.FileHashes == nil {
+		return ""
+	}
+	return fileInfo.Sha1
+}
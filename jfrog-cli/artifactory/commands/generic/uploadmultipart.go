@@ -0,0 +1,446 @@
+package generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	multipartUploadApi = "api/v1/uploads/"
+	// The header, by which the requests of a multipart upload refer to the upload.
+	multipartTokenHeader = "X-JFrog-Upload-Token"
+	// The statuses of a completed multipart upload. Any other status means that the upload failed.
+	multipartStatusProcessing = "PROCESSING"
+	multipartStatusFinished   = "FINISHED"
+)
+
+// The interval between the checks of the status of a completed multipart upload, and the time after which the upload
+// fails if Artifactory is still assembling its parts.
+const (
+	multipartStatusInterval = 2 * time.Second
+	multipartStatusTimeout  = 30 * time.Minute
+)
+
+// A local file, which is uploaded in parts since it is larger than the chunk size.
+type multipartFile struct {
+	localPath  string
+	targetPath string
+	size       int64
+}
+
+// Returns the files matching the upload params, which are larger than the chunk size, keyed by target URL path.
+func createMultipartFiles(uploadParams services.UploadParams, chunkSize int64, artifactoryUrl string, checksums *targetChecksumsCache) (map[string]multipartFile, error) {
+	files, err := collectFilesForUpload(uploadParams, checksums)
+	if err != nil {
+		return nil, err
+	}
+	multipartFiles := make(map[string]multipartFile)
+	for _, file := range files {
+		if file.isDir || (file.symlink != "" && uploadParams.IsSymlink()) {
+			continue
+		}
+		info, err := os.Stat(file.localPath)
+		if errorutils.CheckError(err) != nil {
+			return nil, err
+		}
+		if info.Size() <= chunkSize {
+			continue
+		}
+		targetUrl, err := clientutils.BuildArtifactoryUrl(artifactoryUrl, file.targetPath, make(map[string]string))
+		if err != nil {
+			return nil, err
+		}
+		parsedUrl, err := url.Parse(targetUrl)
+		if errorutils.CheckError(err) != nil {
+			return nil, err
+		}
+		multipartFiles[parsedUrl.Path] = multipartFile{localPath: file.localPath, targetPath: file.targetPath, size: info.Size()}
+	}
+	return multipartFiles, nil
+}
+
+// The maximum number of parts uploaded at a time by all of the threads of an upload service. The split count is
+// reduced, so that the threads × split count connections of the parts stay within it.
+const maxMultipartConnections = 64
+
+// Returns the split count of the multipart uploads of each thread, reduced so that the parts uploaded at a time by all
+// of the threads do not exceed maxMultipartConnections.
+func getMultipartSplitCount(splitCount, threads int) int {
+	if splitCount <= 0 {
+		splitCount = 1
+	}
+	if threads <= 0 || threads*splitCount <= maxMultipartConnections {
+		return splitCount
+	}
+	bounded := maxMultipartConnections / threads
+	if bounded < 1 {
+		bounded = 1
+	}
+	log.Warn("Uploading", strconv.Itoa(bounded), "parts of each file at a time rather than", strconv.Itoa(splitCount)+", so that the", strconv.Itoa(threads), "threads upload at most", strconv.Itoa(maxMultipartConnections), "parts at a time.")
+	return bounded
+}
+
+// An http.RoundTripper, which uploads the files larger than the chunk size in parts, rather than in a single request.
+// The parts are uploaded in parallel to the presigned URLs, which Artifactory returns for each of them. Once all of the
+// parts are uploaded, the upload is completed, and its status is checked until Artifactory reassembles the file.
+// Each part is retried separately, up to the retries of the upload. If a part still fails and the upload service retries the file, only the parts which were not confirmed
+// by Artifactory are uploaded again, continuing the same multipart upload.
+// If Artifactory does not support multipart uploads for the target, the file is uploaded in a single request.
+type multipartTransport struct {
+	transport      http.RoundTripper
+	artifactoryUrl string
+	chunkSize      int64
+	// The parts of each file uploaded at a time. Each of the threads of the upload service uploads its own file in
+	// parts, so up to threads × splitCount connections are open at a time.
+	splitCount int
+	// The files of the spec file currently being uploaded, which are uploaded in parts. Set between the uploads of the spec files.
+	files   map[string]multipartFile
+	retries int
+	// The state of the uploads in parts, which were not completed yet. Saved to the state file only when
+	// interrupted uploads are resumed. Otherwise, it is only kept in memory, for the retries of the files.
+	resume *uploadResumeState
+	// Set only when the total retries of the upload are limited.
+	budget *retriesBudget
+}
+
+func (mt *multipartTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPut || req.Header.Get("X-Checksum-Deploy") == "true" {
+		return mt.transport.RoundTrip(req)
+	}
+	path := strings.SplitN(req.URL.Path, ";", 2)
+	file, ok := mt.files[path[0]]
+	if !ok {
+		return mt.transport.RoundTrip(req)
+	}
+	props := ""
+	if len(path) > 1 {
+		props = path[1]
+	}
+	// The state kept in memory is not used once the file changes, so the checksum sent by the upload service is used as its key.
+	key := req.Header.Get("X-Checksum-Sha1")
+	if mt.resume.isSaved() {
+		var err error
+		if key, err = calcSha256(file.localPath); err != nil {
+			return nil, err
+		}
+	}
+	token := mt.resume.getToken(key, file, mt.chunkSize)
+	resumed := token != ""
+	if resumed && mt.resume.isSaved() {
+		log.Info("Resuming the interrupted upload of", file.localPath+".")
+	} else if resumed {
+		log.Info("Retrying the parts of", file.localPath, "which were not uploaded yet.")
+	} else {
+		var supported bool
+		var err error
+		if token, supported, err = mt.createUpload(req, file, props, key); err != nil || !supported {
+			if err != nil {
+				return nil, err
+			}
+			log.Warn("Artifactory does not support multipart upload of", file.targetPath+". Uploading it in a single request.")
+			return mt.transport.RoundTrip(req)
+		}
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	resp, err := mt.uploadAndComplete(req, file, token, key)
+	if resumed && err == nil && resp.StatusCode == http.StatusNotFound {
+		log.Warn("The interrupted upload of", file.localPath, "is no longer available in Artifactory. Starting the upload over.")
+		if err = mt.resume.remove(key); err != nil {
+			return nil, err
+		}
+		var supported bool
+		if token, supported, err = mt.createUpload(req, file, props, key); err != nil || !supported {
+			return resp, err
+		}
+		resp, err = mt.uploadAndComplete(req, file, token, key)
+	}
+	if err == nil && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated) {
+		err = mt.resume.remove(key)
+	}
+	return resp, err
+}
+
+func (mt *multipartTransport) uploadAndComplete(req *http.Request, file multipartFile, token, key string) (*http.Response, error) {
+	if mt.resume.isCompleted(key) {
+		log.Info("All the parts of", file.localPath, "were already uploaded. Waiting for Artifactory to reassemble them.")
+		return mt.waitForUpload(req, file, token, key)
+	}
+	log.Info("Uploading", file.localPath, "in", strconv.FormatInt(mt.getPartsCount(file.size), 10), "parts.")
+	if resp, err := mt.uploadParts(req, file, token, key); resp != nil || err != nil {
+		return resp, err
+	}
+	return mt.completeUpload(req, file, token, key)
+}
+
+func (mt *multipartTransport) getPartsCount(size int64) int64 {
+	return (size + mt.chunkSize - 1) / mt.chunkSize
+}
+
+// Starts the multipart upload, and returns its token.
+// Returns false if Artifactory does not support multipart uploads.
+// If the upload can be resumed, it is saved to the resume state under the key.
+func (mt *multipartTransport) createUpload(req *http.Request, file multipartFile, props, key string) (token string, supported bool, err error) {
+	repoKey, repoPath := file.targetPath, ""
+	if i := strings.Index(file.targetPath, "/"); i >= 0 {
+		repoKey, repoPath = file.targetPath[:i], file.targetPath[i+1:]
+	}
+	params := map[string]string{
+		"repoKey":  repoKey,
+		"repoPath": repoPath,
+		"partSize": strconv.FormatInt(mt.chunkSize, 10),
+		"fileSize": strconv.FormatInt(file.size, 10),
+		"sha1":     req.Header.Get("X-Checksum-Sha1"),
+	}
+	if props != "" {
+		params["properties"] = props
+	}
+	resp, body, err := mt.send(req, http.MethodPost, "new", "", params)
+	if err != nil {
+		return "", false, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented, http.StatusBadRequest:
+		return "", false, nil
+	default:
+		return "", false, errorutils.CheckError(errors.New("Failed starting the multipart upload of " + file.localPath + ". Artifactory response: " + resp.Status))
+	}
+	result := new(struct {
+		Token string `json:"token"`
+	})
+	if err = json.Unmarshal(body, result); errorutils.CheckError(err) != nil {
+		return "", false, err
+	}
+	return result.Token, true, mt.resume.start(key, file, result.Token, mt.chunkSize)
+}
+
+// Uploads the parts of the file in parallel.
+// Returns the failed response or error of the first part, which failed after all of its retries. Once a part fails,
+// no more parts are started, since the upload service retries the file anyway.
+// Parts which were already confirmed by Artifactory before the upload was interrupted are skipped.
+func (mt *multipartTransport) uploadParts(req *http.Request, file multipartFile, token, key string) (*http.Response, error) {
+	localFile, err := os.Open(file.localPath)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	defer localFile.Close()
+
+	var once sync.Once
+	var failed int32
+	var failedResp *http.Response
+	var failedErr error
+	var wg sync.WaitGroup
+	semaphore := make(chan bool, mt.splitCount)
+	for part := int64(0); part < mt.getPartsCount(file.size); part++ {
+		if mt.resume.isConfirmed(key, part+1) {
+			continue
+		}
+		offset := part * mt.chunkSize
+		length := mt.chunkSize
+		if offset+length > file.size {
+			length = file.size - offset
+		}
+		semaphore <- true
+		// A part may fail while waiting for a free slot, so the flag is checked only once the slot is taken.
+		if atomic.LoadInt32(&failed) != 0 {
+			<-semaphore
+			break
+		}
+		wg.Add(1)
+		go func(partNumber, offset, length int64) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			resp, err := mt.uploadPart(req, token, partNumber, io.NewSectionReader(localFile, offset, length), length)
+			if err == nil && resp == nil {
+				err = mt.resume.confirm(key, partNumber)
+			}
+			if err != nil || resp != nil {
+				once.Do(func() { failedResp, failedErr = resp, err })
+				atomic.StoreInt32(&failed, 1)
+			}
+		}(part+1, offset, length)
+	}
+	wg.Wait()
+	return failedResp, failedErr
+}
+
+// Uploads a single part to its presigned URL, retrying it on failure. Returns the response or error of the last attempt,
+// if it failed.
+// The presigned URLs expire, so each attempt uploads the part to a new URL.
+func (mt *multipartTransport) uploadPart(req *http.Request, token string, partNumber int64, content *io.SectionReader, length int64) (*http.Response, error) {
+	var resp *http.Response
+	var body []byte
+	var err error
+	for i := 0; i <= mt.retries && (i == 0 || mt.budget.consume()); i++ {
+		var partUrl string
+		if partUrl, resp, err = mt.getPartUrl(req, token, partNumber); resp != nil || err != nil {
+			return resp, err
+		}
+		if _, err = content.Seek(0, io.SeekStart); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		resp, body, err = mt.putPart(partUrl, content, length)
+		if err == nil && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated) {
+			return nil, nil
+		}
+		log.Warn("Upload attempt #"+strconv.Itoa(i+1), "of part", strconv.FormatInt(partNumber, 10), "failed -", getPartFailureReason(resp, err))
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func getPartFailureReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+// Returns the presigned URL, to which the part is uploaded.
+// If Artifactory fails to return it, its response is returned instead.
+func (mt *multipartTransport) getPartUrl(req *http.Request, token string, partNumber int64) (string, *http.Response, error) {
+	resp, body, err := mt.send(req, http.MethodPost, "urls", token, map[string]string{"partNumber": strconv.FormatInt(partNumber, 10)})
+	if err != nil {
+		return "", nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return "", resp, nil
+	}
+	result := new(struct {
+		Urls []struct {
+			PartNumber int64  `json:"partNumber"`
+			Url        string `json:"url"`
+		} `json:"urls"`
+	})
+	if err = json.Unmarshal(body, result); errorutils.CheckError(err) != nil {
+		return "", nil, err
+	}
+	for _, partUrl := range result.Urls {
+		if partUrl.PartNumber == partNumber {
+			return partUrl.Url, nil, nil
+		}
+	}
+	return "", nil, errorutils.CheckError(errors.New("Artifactory did not return the URL of part " + strconv.FormatInt(partNumber, 10) + " of the multipart upload."))
+}
+
+// Uploads the content of a part to its presigned URL.
+// The URL is signed by Artifactory, so the authentication headers of the upload are not sent with it.
+func (mt *multipartTransport) putPart(partUrl string, content io.Reader, length int64) (*http.Response, []byte, error) {
+	partReq, err := http.NewRequest(http.MethodPut, partUrl, content)
+	if errorutils.CheckError(err) != nil {
+		return nil, nil, err
+	}
+	partReq.ContentLength = length
+	return mt.roundTrip(partReq)
+}
+
+// Completes the multipart upload after all of its parts were uploaded, and waits for Artifactory to reassemble the file.
+// The response is returned to the upload service, as if the file was uploaded in a single request.
+func (mt *multipartTransport) completeUpload(req *http.Request, file multipartFile, token, key string) (*http.Response, error) {
+	resp, body, err := mt.send(req, http.MethodPost, "complete", token, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	}
+	if err = mt.resume.complete(key); err != nil {
+		return nil, err
+	}
+	return mt.waitForUpload(req, file, token, key)
+}
+
+// Checks the status of the completed multipart upload, until Artifactory finishes reassembling the file.
+func (mt *multipartTransport) waitForUpload(req *http.Request, file multipartFile, token, key string) (*http.Response, error) {
+	for waited := time.Duration(0); ; waited += multipartStatusInterval {
+		resp, body, err := mt.send(req, http.MethodPost, "status", token, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			return resp, nil
+		}
+		result := new(struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		})
+		if err = json.Unmarshal(body, result); errorutils.CheckError(err) != nil {
+			return nil, err
+		}
+		switch result.Status {
+		case multipartStatusFinished:
+			return &http.Response{Status: "201 Created", StatusCode: http.StatusCreated, Header: make(http.Header), Body: ioutil.NopCloser(bytes.NewReader(nil)), Request: req}, nil
+		case multipartStatusProcessing:
+			if waited >= multipartStatusTimeout {
+				return nil, errorutils.CheckError(errors.New("Timed out waiting for Artifactory to reassemble the parts of " + file.localPath + "."))
+			}
+			sleep(multipartStatusInterval)
+		default:
+			// A failed upload can not be continued, so the file is uploaded from scratch if it is retried.
+			if err = mt.resume.remove(key); err != nil {
+				return nil, err
+			}
+			return nil, errorutils.CheckError(errors.New("The multipart upload of " + file.localPath + " failed with status " + result.Status + ": " + result.Error))
+		}
+	}
+}
+
+// Sends a request to the multipart upload API, with the headers of the original upload request.
+// The token refers to the multipart upload, once it is created.
+func (mt *multipartTransport) send(req *http.Request, method, api, token string, params map[string]string) (*http.Response, []byte, error) {
+	apiUrl, err := clientutils.BuildArtifactoryUrl(mt.artifactoryUrl, multipartUploadApi+api, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	apiReq, err := http.NewRequest(method, apiUrl, nil)
+	if errorutils.CheckError(err) != nil {
+		return nil, nil, err
+	}
+	// The checksums and the other upload headers refer to the whole file, so only the other headers, such as the
+	// authentication headers, are sent.
+	apiReq.Header = req.Header.Clone()
+	for name := range apiReq.Header {
+		if strings.HasPrefix(name, "X-Checksum") || name == "X-Explode-Archive" {
+			apiReq.Header.Del(name)
+		}
+	}
+	if token != "" {
+		apiReq.Header.Set(multipartTokenHeader, token)
+	}
+	return mt.roundTrip(apiReq)
+}
+
+func (mt *multipartTransport) roundTrip(req *http.Request) (*http.Response, []byte, error) {
+	resp, err := mt.transport.RoundTrip(req)
+	if errorutils.CheckError(err) != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	return resp, body, errorutils.CheckError(err)
+}
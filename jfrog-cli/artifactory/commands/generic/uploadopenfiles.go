@@ -0,0 +1,82 @@
+package generic
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// The file descriptors, which are left for the other files and sockets of the process, when the maximum number of
+// simultaneously open source files is derived from the open files limit.
+const reservedFileDescriptors = 64
+
+// Returns the maximum number of source files, which are opened simultaneously by default, or 0 if there is no limit.
+// Each file being uploaded is held together with a connection to Artifactory, so half of the open files limit of the
+// process is used, after leaving some file descriptors for the rest of the process.
+func getDefaultMaxOpenFiles() int {
+	openFilesLimit := getOpenFilesLimit()
+	if openFilesLimit <= 0 {
+		return 0
+	}
+	maxOpenFiles := (openFilesLimit - reservedFileDescriptors) / 2
+	if maxOpenFiles < 1 {
+		return 1
+	}
+	return maxOpenFiles
+}
+
+// Returns the maximum number of source files, which are open simultaneously during the upload, or 0 if it is not limited.
+func getMaxOpenFiles(configuration *UploadConfiguration) int {
+	switch {
+	case configuration.MaxOpenFiles == 0:
+		return getDefaultMaxOpenFiles()
+	case configuration.MaxOpenFiles < 0:
+		return 0
+	}
+	return configuration.MaxOpenFiles
+}
+
+// Wraps the transports of the uploaders with the transports limiting the files, whose content is uploaded
+// simultaneously. The uploaders share the same limit, so it applies to the whole upload regardless of the threads.
+func wrapOpenFilesTransports(uploaders []*specUploader, maxOpenFiles int) {
+	openFiles := make(chan struct{}, maxOpenFiles)
+	for _, uploader := range uploaders {
+		httpClient := uploader.uploadService.GetJfrogHttpClient().Client
+		httpClient.Transport = &openFilesTransport{transport: getTransport(httpClient), openFiles: openFiles}
+	}
+}
+
+// An http.RoundTripper, which acquires a slot of the open files semaphore before sending the content of a file, and
+// releases it once the response to the upload is closed, after which the upload service closes the file.
+// The checksum deploy requests and the other requests, which send no file content, are not limited.
+// It wraps all of the other transports, so that the slot is held across the retries of the upload.
+type openFilesTransport struct {
+	transport http.RoundTripper
+	openFiles chan struct{}
+}
+
+func (oft *openFilesTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPut || req.Body == nil || req.Header.Get("X-Checksum-Deploy") == "true" {
+		return oft.transport.RoundTrip(req)
+	}
+	oft.openFiles <- struct{}{}
+	release := new(sync.Once)
+	resp, err := oft.transport.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		release.Do(func() { <-oft.openFiles })
+		return resp, err
+	}
+	resp.Body = &openFileReleaser{ReadCloser: resp.Body, release: func() { release.Do(func() { <-oft.openFiles }) }}
+	return resp, nil
+}
+
+// The body of an upload response, which releases the slot of the uploaded file when it is closed.
+type openFileReleaser struct {
+	io.ReadCloser
+	release func()
+}
+
+func (ofr *openFileReleaser) Close() error {
+	defer ofr.release()
+	return ofr.ReadCloser.Close()
+}
@@ -0,0 +1,22 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package generic
+
+import (
+	"math"
+	"syscall"
+)
+
+// This file will be compiled only on unix systems.
+// Returns the soft limit of open files of the process, or 0 if it is unknown or unlimited.
+func getOpenFilesLimit() int {
+	var rlimit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlimit); err != nil {
+		return 0
+	}
+	if uint64(rlimit.Cur) > math.MaxInt32 {
+		return 0
+	}
+	return int(rlimit.Cur)
+}
@@ -0,0 +1,7 @@
+package generic
+
+// This file will be compiled on windows.
+// Windows has no open files limit, which is exhausted by the upload threads.
+func getOpenFilesLimit() int {
+	return 0
+}
@@ -0,0 +1,71 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"regexp"
+	"strings"
+)
+
+var pathPropsKeyRegexp = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// Extracts props from the local paths of the uploaded files, according to a template such as releases/{channel}/*.
+// Each {key} in the template matches a single path segment, which is attached to the file as the value of the key prop.
+// A * matches any part of a single path segment. The template is matched against the end of the path.
+// The keys are unrelated to the {1}, {2}... placeholders of the pattern's capture groups, which are resolved separately,
+// so numeric keys are not allowed.
+type pathPropsTemplate struct {
+	regexp *regexp.Regexp
+	keys   []string
+}
+
+// Returns nil if the template is empty.
+func parsePathPropsTemplate(template string) (*pathPropsTemplate, error) {
+	if template == "" {
+		return nil, nil
+	}
+	pathProps := &pathPropsTemplate{}
+	expression, last := "", 0
+	normalized := strings.Replace(template, "\\", "/", -1)
+	for _, match := range pathPropsKeyRegexp.FindAllStringSubmatchIndex(normalized, -1) {
+		key := normalized[match[2]:match[3]]
+		if key == "" || strings.ContainsAny(key, "/*=;,") || placeholderRegexp.MatchString("{"+key+"}") {
+			return nil, errorutils.CheckError(errors.New("The path to props template should contain {key} segments, whose keys are not numeric, but got: " + template))
+		}
+		pathProps.keys = append(pathProps.keys, key)
+		expression += quotePathPropsTemplate(normalized[last:match[0]]) + "([^/]+)"
+		last = match[1]
+	}
+	if len(pathProps.keys) == 0 {
+		return nil, errorutils.CheckError(errors.New("The path to props template should contain at least one {key} segment, but got: " + template))
+	}
+	expression += quotePathPropsTemplate(normalized[last:])
+	pathProps.regexp = regexp.MustCompile("(?:^|/)" + strings.TrimPrefix(expression, "/") + "$")
+	return pathProps, nil
+}
+
+// Quotes the literal parts of the template, except for the * wildcards.
+func quotePathPropsTemplate(literal string) string {
+	parts := strings.Split(literal, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return strings.Join(parts, "[^/]*")
+}
+
+// Returns the props extracted from the local path, in the form of "key1=value1;key2=value2",
+// or an empty string if the path does not match the template.
+func (pathProps *pathPropsTemplate) getProps(localPath string) string {
+	if pathProps == nil {
+		return ""
+	}
+	match := pathProps.regexp.FindStringSubmatch(strings.Replace(localPath, "\\", "/", -1))
+	if match == nil {
+		return ""
+	}
+	var props []string
+	for i, key := range pathProps.keys {
+		props = append(props, key+"="+match[i+1])
+	}
+	return strings.Join(props, ";")
+}
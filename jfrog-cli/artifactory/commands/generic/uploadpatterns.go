@@ -0,0 +1,170 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"regexp"
+	"strings"
+)
+
+// Matches a brace group of a wildcard pattern, such as {linux,darwin}, which has at least two alternatives.
+var braceGroupRegExp = regexp.MustCompile(`\{[^{}/]*,[^{}/]*\}`)
+
+// Sets the pattern of the upload params according to the pattern type, and verifies that it is valid for that type.
+// The client supports wildcard and regexp patterns, so Ant-style patterns are converted to regular expressions.
+func setUploadPattern(uploadParams *services.UploadParams, patternType string) error {
+	pattern := uploadParams.GetPattern()
+	switch patternType {
+	case spec.AntPatternType:
+		// A pattern without wildcards is a path, which is uploaded as is, the same way it is with the other pattern types.
+		if pattern == StdinPattern || (!strings.HasSuffix(pattern, "/") && !strings.ContainsAny(pattern, "*?()")) {
+			uploadParams.Regexp = false
+			break
+		}
+		converted, err := antPatternToRegExp(pattern)
+		if err != nil {
+			return err
+		}
+		uploadParams.SetPattern(converted)
+		uploadParams.Regexp = true
+	case spec.RegexpPatternType:
+		if _, err := regexp.Compile(pattern); err != nil {
+			return errorutils.CheckError(errors.New("The pattern '" + pattern + "' is not a valid regular expression: " + err.Error()))
+		}
+		uploadParams.Regexp = true
+	default:
+		if braceGroupRegExp.MatchString(pattern) {
+			converted, err := wildcardPatternToRegExp(pattern)
+			if err != nil {
+				return err
+			}
+			uploadParams.SetPattern(converted)
+			uploadParams.Regexp = true
+			break
+		}
+		// A pattern without wildcards may be the path of a single file, which is uploaded without matching.
+		if !strings.Contains(pattern, "*") {
+			break
+		}
+		if _, err := regexp.Compile(clientutils.PrepareLocalPathForUpload(pattern, false)); err != nil {
+			return errorutils.CheckError(errors.New("The pattern '" + pattern + "' is not a valid wildcard pattern: " + err.Error()))
+		}
+		uploadParams.Regexp = false
+	}
+	return nil
+}
+
+// Converts an Ant-style pattern to a regular expression, which matches the same paths.
+// ** matches any number of directories, and must be a whole path segment. * and ? match any number of characters
+// and a single character within a path segment. Parentheses are kept, so they can be referred to by placeholders
+// in the target, the same way they are in wildcard patterns.
+// The path segments which precede the first segment with wildcards are kept as is, since the client takes them
+// as the root path of the pattern.
+func antPatternToRegExp(pattern string) (string, error) {
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	sections := strings.Split(pattern, "/")
+	converted := ""
+	isRoot := true
+	for i, section := range sections {
+		isLast := i == len(sections)-1
+		if isRoot && !strings.ContainsAny(section, "*?()") {
+			converted += section
+			if !isLast {
+				converted += "/"
+			}
+			continue
+		}
+		isRoot = false
+		if section == "**" {
+			if isLast {
+				converted += "(?:.*)"
+			} else {
+				converted += "(?:.*/)?"
+			}
+			continue
+		}
+		if strings.Contains(section, "**") {
+			return "", errorutils.CheckError(errors.New("The Ant-style pattern '" + pattern + "' is invalid: '**' must be a whole path segment, but got '" + section + "'."))
+		}
+		for _, char := range section {
+			switch char {
+			case '*':
+				converted += "(?:[^/]*)"
+			case '?':
+				converted += "(?:[^/])"
+			case '(', ')':
+				converted += string(char)
+			default:
+				converted += regexp.QuoteMeta(string(char))
+			}
+		}
+		if !isLast {
+			converted += "/"
+		}
+	}
+	converted += "$"
+	if _, err := regexp.Compile(converted); err != nil {
+		return "", errorutils.CheckError(errors.New("The Ant-style pattern '" + pattern + "' is invalid: " + err.Error()))
+	}
+	return converted, nil
+}
+
+// Converts a wildcard pattern with brace groups, such as "dist/{linux,darwin}/*.tar.gz", to a regular expression,
+// in which each of the groups matches any of its comma separated alternatives. The groups are not capturing, so they
+// do not affect the placeholders of the parts of the pattern enclosed in parenthesis.
+// The rest of the pattern is converted the same way the client converts wildcard patterns, and the path segments which
+// precede the first segment with wildcards or groups are kept as is, since the client takes them as the root path.
+func wildcardPatternToRegExp(pattern string) (string, error) {
+	sections := strings.Split(pattern, "/")
+	converted := ""
+	isRoot := true
+	for i, section := range sections {
+		isLast := i == len(sections)-1
+		if isRoot && !strings.ContainsAny(section, "*()") && !braceGroupRegExp.MatchString(section) {
+			converted += section
+			if !isLast {
+				converted += "/"
+			}
+			continue
+		}
+		isRoot = false
+		for section != "" {
+			group := braceGroupRegExp.FindStringIndex(section)
+			if group == nil {
+				converted += wildcardToRegExp(section)
+				break
+			}
+			converted += wildcardToRegExp(section[:group[0]])
+			var alternatives []string
+			for _, alternative := range strings.Split(section[group[0]+1:group[1]-1], ",") {
+				alternatives = append(alternatives, wildcardToRegExp(alternative))
+			}
+			converted += "(?:" + strings.Join(alternatives, "|") + ")"
+			section = section[group[1]:]
+		}
+		if !isLast {
+			converted += "/"
+		}
+	}
+	if strings.HasSuffix(pattern, "/") {
+		converted += ".*"
+	}
+	converted += "$"
+	if _, err := regexp.Compile(converted); err != nil {
+		return "", errorutils.CheckError(errors.New("The pattern '" + pattern + "' is not a valid wildcard pattern: " + err.Error()))
+	}
+	return converted, nil
+}
+
+// Converts a part of a wildcard pattern to a regular expression, the same way the client does.
+func wildcardToRegExp(pattern string) string {
+	for _, char := range []string{".", "^", "$", "+"} {
+		pattern = strings.Replace(pattern, char, "\\"+char, -1)
+	}
+	return strings.Replace(pattern, "*", ".*", -1)
+}
@@ -0,0 +1,161 @@
+package generic
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/crypto/ssh/terminal"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	progressBarWidth           = 30
+	progressBarRefreshInterval = 200 * time.Millisecond
+	defaultProgressInterval    = 10
+)
+
+// Tracks the progress of an upload, and periodically reports it.
+// When stdout is a terminal, the progress is rendered as a progress bar. Otherwise, it is logged at a fixed interval.
+type uploadProgress struct {
+	totalFiles     int64
+	completedFiles int64
+	bytes          int64
+	isTerminal     bool
+	interval       time.Duration
+	done           chan bool
+	wg             sync.WaitGroup
+}
+
+func newUploadProgress(totalFiles int, intervalSecs int) *uploadProgress {
+	progress := &uploadProgress{totalFiles: int64(totalFiles), done: make(chan bool)}
+	progress.isTerminal = terminal.IsTerminal(int(os.Stdout.Fd()))
+	if progress.isTerminal {
+		progress.interval = progressBarRefreshInterval
+	} else {
+		if intervalSecs <= 0 {
+			intervalSecs = defaultProgressInterval
+		}
+		progress.interval = time.Duration(intervalSecs) * time.Second
+	}
+	return progress
+}
+
+func (p *uploadProgress) start() {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-ticker.C:
+				p.report()
+			}
+		}
+	}()
+}
+
+// Stops reporting the progress, after reporting it one last time.
+func (p *uploadProgress) stop() {
+	close(p.done)
+	p.wg.Wait()
+	p.report()
+	if p.isTerminal {
+		fmt.Fprintln(os.Stdout)
+	}
+}
+
+func (p *uploadProgress) addBytes(n int64) {
+	atomic.AddInt64(&p.bytes, n)
+}
+
+func (p *uploadProgress) incCompletedFiles() {
+	atomic.AddInt64(&p.completedFiles, 1)
+}
+
+func (p *uploadProgress) report() {
+	completed := atomic.LoadInt64(&p.completedFiles)
+	bytes := atomic.LoadInt64(&p.bytes)
+	if !p.isTerminal {
+		log.Info(fmt.Sprintf("Upload progress: %d/%d files, %s transferred.", completed, p.totalFiles, formatBytes(bytes)))
+		return
+	}
+	filled := progressBarWidth
+	if p.totalFiles > 0 && completed < p.totalFiles {
+		filled = int(completed * progressBarWidth / p.totalFiles)
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	fmt.Fprintf(os.Stdout, "\r[%s] %d/%d files, %s transferred", bar, completed, p.totalFiles, formatBytes(bytes))
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// An http.RoundTripper, which reports the bytes sent and the files completed by the upload requests to an uploadProgress.
+type progressTransport struct {
+	transport http.RoundTripper
+	progress  *uploadProgress
+}
+
+func (pt *progressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPut {
+		return pt.transport.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body = &progressReader{ReadCloser: req.Body, progress: pt.progress}
+	}
+	resp, err := pt.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	// Checksum deploy requests, which are not satisfied, are followed by a regular upload of the file.
+	// Folders created in Artifactory (when uploading with include-dirs) are not counted as files.
+	isFolder := strings.HasSuffix(strings.SplitN(req.URL.Path, ";", 2)[0], "/")
+	if (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated) && !isFolder {
+		pt.progress.incCompletedFiles()
+	}
+	return resp, err
+}
+
+type progressReader struct {
+	io.ReadCloser
+	progress *uploadProgress
+}
+
+func (pr *progressReader) Read(p []byte) (n int, err error) {
+	n, err = pr.ReadCloser.Read(p)
+	pr.progress.addBytes(int64(n))
+	return
+}
+
+// Starts reporting the progress of the upload of the files by the uploaders, unless the upload is quiet, a dry run, or
+// of a single file. Returns nil if the progress is not reported.
+func startUploadProgress(uploaders []*specUploader, filesCount int, configuration *UploadConfiguration) *uploadProgress {
+	if configuration.Quiet || configuration.DryRun || filesCount <= 1 {
+		return nil
+	}
+	progress := newUploadProgress(filesCount, configuration.ProgressInterval)
+	for _, uploader := range uploaders {
+		httpClient := uploader.uploadService.GetJfrogHttpClient().Client
+		httpClient.Transport = &progressTransport{transport: getTransport(httpClient), progress: progress}
+	}
+	progress.start()
+	return progress
+}
@@ -0,0 +1,79 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var placeholderRegexp = regexp.MustCompile(`\{\d+\}`)
+
+func hasPlaceholders(value string) bool {
+	return placeholderRegexp.MatchString(value)
+}
+
+// Returns the props of each of the files matching the upload params, after replacing the {1}, {2}... placeholders in
+// the props with the values captured from the path of the file. The returned map is keyed by the target URL path.
+// The values are captured by the parenthesized groups of the pattern. When the regexp option is used, these are the
+// regular expression's capture groups. Otherwise, these are the parenthesized parts of the wildcard pattern.
+// The props extracted from the path of the file by the path props template, if set, are added to the resolved props,
+// followed by the props mapped to the extension of the file by the ext props mapping, if set.
+func createPlaceholderProps(uploadParams services.UploadParams, artifactoryUrl string, pathProps *pathPropsTemplate, extProps *extPropsMapping, checksums *targetChecksumsCache) (map[string]string, error) {
+	files, err := collectFilesForUpload(uploadParams, checksums)
+	if err != nil {
+		return nil, err
+	}
+	props := make(map[string]string, len(files))
+	for _, file := range files {
+		targetUrl, err := clientutils.BuildArtifactoryUrl(artifactoryUrl, file.targetPath, make(map[string]string))
+		if err != nil {
+			return nil, err
+		}
+		parsedUrl, err := url.Parse(targetUrl)
+		if errorutils.CheckError(err) != nil {
+			return nil, err
+		}
+		fileProps := resolvePlaceholders(uploadParams.GetProps(), file.placeholders)
+		addProps(&fileProps, pathProps.getProps(file.localPath))
+		addProps(&fileProps, extProps.getProps(file.localPath))
+		props[parsedUrl.Path] = fileProps
+	}
+	return props, nil
+}
+
+// An http.RoundTripper, which replaces the props of the upload requests with the props resolved for their target path.
+type placeholderPropsTransport struct {
+	transport http.RoundTripper
+	// The resolved props of the spec file currently being uploaded, keyed by target URL path. Set between the uploads of the spec files.
+	props     map[string]string
+	debConfig string
+	// Set only when props are extracted from the paths of the files.
+	pathProps *pathPropsTemplate
+	// Set only when props are mapped to the extensions of the files.
+	extProps *extPropsMapping
+}
+
+func (pt *placeholderPropsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPut || pt.props == nil {
+		return pt.transport.RoundTrip(req)
+	}
+	props, ok := pt.props[strings.SplitN(req.URL.Path, ";", 2)[0]]
+	if !ok {
+		return pt.transport.RoundTrip(req)
+	}
+	targetUrl, err := addPropsToUrl(strings.SplitN(req.URL.String(), ";", 2)[0], props, pt.debConfig)
+	if err != nil {
+		return nil, err
+	}
+	parsedUrl, err := url.Parse(targetUrl)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	propsReq := *req
+	propsReq.URL = parsedUrl
+	return pt.transport.RoundTrip(&propsReq)
+}
@@ -0,0 +1,92 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Makes sure that the uploaded props are fully applied to the uploaded artifacts.
+// The props of each of the artifacts are read back from Artifactory, and the missing props are set separately from
+// the upload, up to the retries of the upload params.
+// The requests setting the missing props are limited by the limiter.
+// Returns the artifacts whose props are fully applied, and the artifacts whose props could not be applied.
+func applyPropsAtomically(artifacts []clientutils.FileInfo, uploadParams services.UploadParams, uploadService *services.UploadService, servicesManager *artifactory.ArtifactoryServicesManager, limiter *requestRateLimiter, checksums *targetChecksumsCache) (applied, failed []clientutils.FileInfo) {
+	// The placeholders of the props are resolved for each of the files, the same as they are by the upload.
+	placeholders := make(map[string][]string)
+	if files, err := collectFilesForUpload(uploadParams, checksums); err == nil {
+		for _, file := range files {
+			placeholders[file.localPath] = file.placeholders
+		}
+	}
+	uploadedProps := uploadParams.GetProps()
+	addProps(&uploadedProps, getDebianProps(uploadParams.GetDebian()))
+	for _, artifact := range artifacts {
+		targetPath := getRelativeTargetPath(artifact.ArtifactoryPath, uploadService.ArtDetails.GetUrl())
+		props := resolvePlaceholders(uploadedProps, placeholders[artifact.LocalPath])
+		if err := applyArtifactProps(targetPath, props, uploadParams.GetRetries(), uploadService, servicesManager, limiter); err != nil {
+			log.Error("Failed applying the properties of", targetPath+":", err)
+			failed = append(failed, artifact)
+			continue
+		}
+		applied = append(applied, artifact)
+	}
+	return
+}
+
+// Sets the props, which are missing from the artifact in the target path, until all of the props are applied.
+func applyArtifactProps(targetPath, props string, retries int, uploadService *services.UploadService, servicesManager *artifactory.ArtifactoryServicesManager, limiter *requestRateLimiter) error {
+	expected, err := clientutils.ParseProperties(props, clientutils.SplitCommas)
+	if err != nil || len(expected.Properties) == 0 {
+		return err
+	}
+	for attempt := 0; ; attempt++ {
+		existingProps, err := getArtifactProps(targetPath, uploadService)
+		if err != nil {
+			return err
+		}
+		missing := getMissingProps(expected.Properties, existingProps)
+		if missing == "" {
+			return nil
+		}
+		if attempt > retries {
+			return errorutils.CheckError(errors.New("The properties are still missing after " + strconv.Itoa(attempt) + " attempts to set them: " + missing))
+		}
+		log.Debug("Setting the missing properties of", targetPath, "(attempt", strconv.Itoa(attempt+1)+"):", missing)
+		limiter.wait()
+		if _, err = servicesManager.SetProps(services.PropsParams{Items: []clientutils.ResultItem{createResultItem(targetPath)}, Props: missing}); err != nil {
+			log.Debug("Failed setting the properties of", targetPath+":", err.Error())
+		}
+	}
+}
+
+// Returns the expected props, which are missing from the existing props, in the form of "key1=value1,value2;key2=value3".
+func getMissingProps(expected []clientutils.Property, existingProps map[string][]string) string {
+	missingValues := make(map[string][]string)
+	for _, property := range expected {
+		if !containsString(existingProps[property.Key], property.Value) {
+			missingValues[property.Key] = append(missingValues[property.Key], property.Value)
+		}
+	}
+	var missing []string
+	for key, values := range missingValues {
+		missing = append(missing, key+"="+strings.Join(values, ","))
+	}
+	sort.Strings(missing)
+	return strings.Join(missing, ";")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
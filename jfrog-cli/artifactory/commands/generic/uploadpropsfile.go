@@ -0,0 +1,39 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Reads the props file, and returns its props in the form of "key1=value1;key2=value2".
+// The props in the file are key=value pairs, separated by new lines or semicolons. Lines starting with # are comments.
+// Environment variables in the pairs, in the form of $VAR or ${VAR}, are replaced by their values.
+func readPropsFile(propsFilePath string) (string, error) {
+	content, err := ioutil.ReadFile(propsFilePath)
+	if errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	var props []string
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, pair := range strings.Split(line, ";") {
+			pair = strings.TrimSpace(os.ExpandEnv(pair))
+			if pair == "" {
+				continue
+			}
+			keyValue := strings.SplitN(pair, "=", 2)
+			if len(keyValue) != 2 || strings.TrimSpace(keyValue[0]) == "" {
+				return "", errorutils.CheckError(errors.New("Line " + strconv.Itoa(i+1) + " of the props file " + propsFilePath + " should contain key=value pairs, but got: " + pair))
+			}
+			props = append(props, strings.TrimSpace(keyValue[0])+"="+strings.TrimSpace(keyValue[1]))
+		}
+	}
+	return strings.Join(props, ";"), nil
+}
@@ -0,0 +1,76 @@
+package generic
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const maxRateLimitChunkSize = 32 * 1024
+
+// Limits the aggregate rate of the bytes read by all of the upload threads.
+// Each read reserves the next free time slot for its bytes, and waits for it without holding the lock,
+// so threads never block each other regardless of their number.
+type rateLimiter struct {
+	bytesPerSec float64
+	chunkSize   int
+	mutex       sync.Mutex
+	next        time.Time
+}
+
+func newRateLimiter(maxRateKbps int) *rateLimiter {
+	bytesPerSec := float64(maxRateKbps) * 1000 / 8
+	chunkSize := int(bytesPerSec / 10)
+	if chunkSize > maxRateLimitChunkSize {
+		chunkSize = maxRateLimitChunkSize
+	}
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	return &rateLimiter{bytesPerSec: bytesPerSec, chunkSize: chunkSize}
+}
+
+// Blocks until the specified number of bytes may be sent.
+func (rl *rateLimiter) wait(n int) {
+	rl.mutex.Lock()
+	now := time.Now()
+	if rl.next.Before(now) {
+		rl.next = now
+	}
+	sendAt := rl.next
+	rl.next = rl.next.Add(time.Duration(float64(n) / rl.bytesPerSec * float64(time.Second)))
+	rl.mutex.Unlock()
+	if wait := sendAt.Sub(now); wait > 0 {
+		sleep(wait)
+	}
+}
+
+// An http.RoundTripper, which limits the rate at which the upload requests bodies are sent.
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	limiter   *rateLimiter
+}
+
+func (rt *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPut && req.Body != nil {
+		req.Body = &rateLimitedReader{ReadCloser: req.Body, limiter: rt.limiter}
+	}
+	return rt.transport.RoundTrip(req)
+}
+
+type rateLimitedReader struct {
+	io.ReadCloser
+	limiter *rateLimiter
+}
+
+func (rr *rateLimitedReader) Read(p []byte) (n int, err error) {
+	if len(p) > rr.limiter.chunkSize {
+		p = p[:rr.limiter.chunkSize]
+	}
+	n, err = rr.ReadCloser.Read(p)
+	if n > 0 {
+		rr.limiter.wait(n)
+	}
+	return
+}
@@ -0,0 +1,125 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// Uploads the content read from the reader to the exact target path, and returns the uploaded artifact.
+// If the reader is also an io.Seeker, it is uploaded the same way files are, so that content which already exists in
+// Artifactory is deployed by checksum. Otherwise, the content is streamed.
+// If build info is collected, the artifact is added to the build info.
+func UploadFromReader(reader io.Reader, target string, configuration *UploadConfiguration) (artifact buildinfo.Artifact, err error) {
+	servicesConfig, err := createUploadServiceConfig(configuration.ArtDetails, configuration, 1)
+	if err != nil {
+		return
+	}
+	uploadService, err := createUploadService(servicesConfig, configuration.ArtDetails, configuration)
+	if err != nil {
+		return
+	}
+	// The content of the reader has no local file, whose changes could be checked.
+	transports, err := wrapUploadTransport(uploadService, nil, configuration)
+	if err != nil {
+		return
+	}
+
+	uploadParams := services.NewUploadParams()
+	uploadParams.ArtifactoryCommonParams = &clientutils.ArtifactoryCommonParams{Target: target}
+	if uploadParams.Deb, err = getDebConfig(configuration); err != nil {
+		return
+	}
+	isCollectBuildInfo := len(configuration.BuildName) > 0 && len(configuration.BuildNumber) > 0
+	if isCollectBuildInfo && !configuration.DryRun {
+		if err = utils.SaveBuildGeneralDetails(configuration.BuildName, configuration.BuildNumber); err != nil {
+			return
+		}
+		if configuration.Project != "" {
+			if err = utils.SaveBuildProject(configuration.BuildName, configuration.BuildNumber, configuration.Project); err != nil {
+				return
+			}
+		}
+		if !configuration.NoBuildProps {
+			props := ""
+			if err = addBuildProps(&props, configuration.BuildName, configuration.BuildNumber, configuration.SkipBuildTimestampProp); err != nil {
+				return
+			}
+			uploadParams.SetProps(props)
+		}
+	}
+
+	var fileInfo *clientutils.FileInfo
+	_, isSeeker := reader.(io.Seeker)
+	if configuration.ChecksumOnlyDeploy && !isSeeker {
+		return artifact, errorutils.CheckError(errors.New("Deploying only by checksum requires a reader, which is also an io.Seeker."))
+	}
+	if isSeeker && !configuration.DryRun {
+		if configuration.ChecksumOnlyDeploy {
+			uploadService.MinChecksumDeploy = 0
+		}
+		if fileInfo, err = uploadSeekableReader(reader, uploadParams, uploadService, transports, configuration); err != nil {
+			return
+		}
+	} else {
+		streamInfo, err := uploadStream("", "the content of the reader", reader, uploadParams, uploadService)
+		if err != nil {
+			return artifact, err
+		}
+		fileInfo = &streamInfo
+	}
+	artifact = fileInfo.ToBuildArtifacts()
+	if isCollectBuildInfo && !configuration.DryRun {
+		populateFunc := func(partial *buildinfo.Partial) {
+			partial.Artifacts = appendBuildArtifacts(partial.Artifacts, []buildinfo.Artifact{artifact})
+		}
+		isAppendable := func(partial *buildinfo.Partial) bool {
+			return partial.ModuleId == "" && partial.Artifacts != nil
+		}
+		err = saveUploadPartial(configuration.BuildName, configuration.BuildNumber, configuration.BuildAppend, isAppendable, populateFunc)
+	}
+	return
+}
+
+// Uploads the content of the seekable reader through the upload service, so that it is deployed by checksum by the same
+// requests and transports as files. The upload service uploads local files only, so the content is first written to
+// a temporary file.
+func uploadSeekableReader(reader io.Reader, uploadParams services.UploadParams, uploadService *services.UploadService, transports *uploadTransports, configuration *UploadConfiguration) (*clientutils.FileInfo, error) {
+	tempFile, err := ioutil.TempFile(getUploadTempDir(configuration), "upload-reader")
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	defer os.Remove(tempFile.Name())
+	_, err = io.Copy(tempFile, reader)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+
+	uploadParams.Pattern = tempFile.Name()
+	uploadParams.Flat = true
+	if err = transports.checksumDeploy.setUploadParams(uploadParams, uploadService.ArtDetails.GetUrl(), nil); err != nil {
+		return nil, err
+	}
+	artifacts, _, failed, err := uploadService.UploadFiles(uploadParams)
+	if err != nil {
+		return nil, err
+	}
+	if failed > 0 || len(artifacts) != 1 {
+		if configuration.ChecksumOnlyDeploy {
+			return nil, errorutils.CheckError(errors.New("The content of the reader could not be deployed to " + uploadParams.GetTarget() + " by checksum, since Artifactory does not have it."))
+		}
+		return nil, errorutils.CheckError(errors.New("Failed uploading the content of the reader to " + uploadParams.GetTarget() + "."))
+	}
+	// The temporary file is not the source of the artifact.
+	artifacts[0].LocalPath = ""
+	return &artifacts[0], nil
+}
@@ -0,0 +1,50 @@
+package generic
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limits the aggregate rate of the requests sent by all of the upload threads.
+// The requests are spread evenly over time, so that bursts of requests are delayed rather than refused.
+// Each request reserves the next free time slot, and waits for it without holding the lock.
+type requestRateLimiter struct {
+	interval time.Duration
+	mutex    sync.Mutex
+	next     time.Time
+}
+
+func newRequestRateLimiter(maxRequestsPerSec int) *requestRateLimiter {
+	return &requestRateLimiter{interval: time.Second / time.Duration(maxRequestsPerSec)}
+}
+
+// Blocks until the next request may be sent. Does not block if the limiter is nil.
+func (rl *requestRateLimiter) wait() {
+	if rl == nil {
+		return
+	}
+	rl.mutex.Lock()
+	now := time.Now()
+	if rl.next.Before(now) {
+		rl.next = now
+	}
+	sendAt := rl.next
+	rl.next = rl.next.Add(rl.interval)
+	rl.mutex.Unlock()
+	if wait := sendAt.Sub(now); wait > 0 {
+		sleep(wait)
+	}
+}
+
+// An http.RoundTripper, which limits the rate at which all of the requests of the upload are sent, including
+// the retries, the parts of multipart uploads and the requests reading the existing artifacts.
+type requestRateLimitTransport struct {
+	transport http.RoundTripper
+	limiter   *requestRateLimiter
+}
+
+func (rt *requestRateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.limiter.wait()
+	return rt.transport.RoundTrip(req)
+}
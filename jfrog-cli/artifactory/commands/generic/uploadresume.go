@@ -0,0 +1,208 @@
+package generic
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const uploadResumeStateFile = "upload-resume.json"
+
+// A multipart upload, which can be resumed by a later upload of the same file.
+type resumedUpload struct {
+	LocalPath  string `json:"localPath"`
+	TargetPath string `json:"targetPath"`
+	Token      string `json:"token"`
+	PartSize   int64  `json:"partSize"`
+	// The numbers of the parts, which were confirmed by Artifactory.
+	Parts []int64 `json:"parts,omitempty"`
+	// Set once Artifactory accepted the completion of the upload. The parts of a completed upload can no longer be
+	// uploaded, so resuming it only waits for Artifactory to reassemble the file.
+	Completed bool `json:"completed,omitempty"`
+}
+
+// The state of the multipart uploads, which were not completed yet, keyed by the SHA256 checksum of the uploaded file.
+// The state is saved after every confirmed part, and the state file is removed once all of the uploads are completed.
+// A state without a path is only kept in memory.
+// The methods of a nil state do nothing.
+type uploadResumeState struct {
+	path    string
+	mutex   sync.Mutex
+	Uploads map[string]*resumedUpload `json:"uploads"`
+}
+
+func getUploadResumeStatePath() (string, error) {
+	homeDir, err := config.GetJfrogHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, uploadResumeStateFile), nil
+}
+
+func newInMemoryUploadResumeState() *uploadResumeState {
+	return &uploadResumeState{Uploads: make(map[string]*resumedUpload)}
+}
+
+func loadUploadResumeState(path string) (*uploadResumeState, error) {
+	state := &uploadResumeState{path: path, Uploads: make(map[string]*resumedUpload)}
+	if !fileutils.IsPathExists(path, false) {
+		return state, nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(content, state); errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	if state.Uploads == nil {
+		state.Uploads = make(map[string]*resumedUpload)
+	}
+	return state, nil
+}
+
+// Loads the saved state of the interrupted uploads in parts into the multipart transport, so that they are resumed.
+func resumeMultipartUploads(transports *uploadTransports) (err error) {
+	if transports.multipart == nil {
+		return errorutils.CheckError(errors.New("Resuming uploads requires a chunk size, since only uploads in parts can be resumed."))
+	}
+	statePath, err := getUploadResumeStatePath()
+	if err != nil {
+		return
+	}
+	transports.multipart.resume, err = loadUploadResumeState(statePath)
+	return
+}
+
+// Returns true if the state is saved to the state file, so that it can be resumed by later uploads.
+func (state *uploadResumeState) isSaved() bool {
+	return state != nil && state.path != ""
+}
+
+// Returns the token of the saved upload of the file, or an empty string if the upload cannot be resumed.
+// Saved uploads of the same local path with a different checksum are discarded, since the file changed since.
+func (state *uploadResumeState) getToken(key string, file multipartFile, partSize int64) string {
+	if state == nil {
+		return ""
+	}
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	for savedKey, upload := range state.Uploads {
+		if upload.LocalPath == file.localPath && savedKey != key {
+			log.Info("The file", file.localPath, "changed since its upload was interrupted. Starting the upload over.")
+			delete(state.Uploads, savedKey)
+		}
+	}
+	upload, ok := state.Uploads[key]
+	if !ok {
+		return ""
+	}
+	if upload.TargetPath != file.targetPath || upload.PartSize != partSize {
+		delete(state.Uploads, key)
+		return ""
+	}
+	return upload.Token
+}
+
+func (state *uploadResumeState) start(key string, file multipartFile, token string, partSize int64) error {
+	if state == nil {
+		return nil
+	}
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.Uploads[key] = &resumedUpload{LocalPath: file.localPath, TargetPath: file.targetPath, Token: token, PartSize: partSize}
+	return state.save()
+}
+
+func (state *uploadResumeState) isConfirmed(key string, partNumber int64) bool {
+	if state == nil {
+		return false
+	}
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	if upload, ok := state.Uploads[key]; ok {
+		for _, part := range upload.Parts {
+			if part == partNumber {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (state *uploadResumeState) confirm(key string, partNumber int64) error {
+	if state == nil {
+		return nil
+	}
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	upload, ok := state.Uploads[key]
+	if !ok {
+		return nil
+	}
+	upload.Parts = append(upload.Parts, partNumber)
+	return state.save()
+}
+
+func (state *uploadResumeState) isCompleted(key string) bool {
+	if state == nil {
+		return false
+	}
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	upload, ok := state.Uploads[key]
+	return ok && upload.Completed
+}
+
+func (state *uploadResumeState) complete(key string) error {
+	if state == nil {
+		return nil
+	}
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	upload, ok := state.Uploads[key]
+	if !ok {
+		return nil
+	}
+	upload.Completed = true
+	return state.save()
+}
+
+func (state *uploadResumeState) remove(key string) error {
+	if state == nil {
+		return nil
+	}
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	delete(state.Uploads, key)
+	return state.save()
+}
+
+// Writes the state to the state file, or removes the state file if there are no uploads to resume.
+// Must be called while holding the mutex.
+func (state *uploadResumeState) save() error {
+	if state.path == "" {
+		return nil
+	}
+	if len(state.Uploads) == 0 {
+		if err := os.Remove(state.path); err != nil && !os.IsNotExist(err) {
+			return errorutils.CheckError(err)
+		}
+		return nil
+	}
+	content, err := json.Marshal(state)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(state.path), 0700); errorutils.CheckError(err) != nil {
+		return err
+	}
+	return errorutils.CheckError(ioutil.WriteFile(state.path, content, 0600))
+}
@@ -0,0 +1,79 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const maxRetryWait = 30 * time.Second
+
+// Replaced in tests.
+var sleep = time.Sleep
+
+// An http.RoundTripper, which waits before resending an upload request that previously failed.
+// The upload service retries failed uploads immediately, so the wait is added here, before the retry reaches the server.
+// The wait grows exponentially with each attempt, with added jitter, and is capped by maxRetryWait.
+type retryWaitTransport struct {
+	transport http.RoundTripper
+	retryWait time.Duration
+	mutex     sync.Mutex
+	// Failed attempts by URL, and the reason of the last failure.
+	failedAttempts map[string]int
+	failureReasons map[string]string
+}
+
+func newRetryWaitTransport(transport http.RoundTripper, retryWaitMilliSecs int) *retryWaitTransport {
+	return &retryWaitTransport{
+		transport:      transport,
+		retryWait:      time.Duration(retryWaitMilliSecs) * time.Millisecond,
+		failedAttempts: make(map[string]int),
+		failureReasons: make(map[string]string),
+	}
+}
+
+func (rt *retryWaitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPut {
+		return rt.transport.RoundTrip(req)
+	}
+	url := req.URL.String()
+	rt.mutex.Lock()
+	attempt, reason := rt.failedAttempts[url], rt.failureReasons[url]
+	rt.mutex.Unlock()
+	if attempt > 0 {
+		wait := rt.getWait(attempt)
+		log.Warn("Retry #"+strconv.Itoa(attempt), "of", url, "in", wait.String(), "after failure -", reason)
+		sleep(wait)
+	}
+
+	resp, err := rt.transport.RoundTrip(req)
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+	switch {
+	case err != nil:
+		rt.failedAttempts[url]++
+		rt.failureReasons[url] = err.Error()
+	case resp.StatusCode >= 500:
+		rt.failedAttempts[url]++
+		rt.failureReasons[url] = resp.Status
+	default:
+		delete(rt.failedAttempts, url)
+		delete(rt.failureReasons, url)
+	}
+	return resp, err
+}
+
+func (rt *retryWaitTransport) getWait(attempt int) time.Duration {
+	wait := rt.retryWait
+	for i := 1; i < attempt && wait < maxRetryWait; i++ {
+		wait *= 2
+	}
+	wait += time.Duration(rand.Int63n(int64(wait)/2 + 1))
+	if wait > maxRetryWait {
+		wait = maxRetryWait
+	}
+	return wait
+}
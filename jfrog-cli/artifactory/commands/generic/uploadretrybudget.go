@@ -0,0 +1,42 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"sync"
+)
+
+// The retries left for the whole upload, shared by all of the files. Each retry of a failed upload request consumes a
+// retry from the budget, and once the budget is exhausted, the failed files are no longer retried.
+type retriesBudget struct {
+	mutex     sync.Mutex
+	remaining int
+	exhausted bool
+}
+
+// Consumes a retry from the budget. Returns false if the budget is exhausted.
+// A nil budget is unlimited.
+func (budget *retriesBudget) consume() bool {
+	if budget == nil {
+		return true
+	}
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+	if budget.remaining > 0 {
+		budget.remaining--
+		return true
+	}
+	if !budget.exhausted {
+		budget.exhausted = true
+		log.Error("The maximum total retries of the upload were exhausted. The failed files are no longer retried.")
+	}
+	return false
+}
+
+func (budget *retriesBudget) isExhausted() bool {
+	if budget == nil {
+		return false
+	}
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+	return budget.exhausted
+}
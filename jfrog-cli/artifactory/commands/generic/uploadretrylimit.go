@@ -0,0 +1,128 @@
+package generic
+
+import (
+	"bytes"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// The last failure of an upload request, either its status or its error.
+type uploadFailure struct {
+	status     string
+	statusCode int
+	err        error
+}
+
+// Returns the failure as the outcome of the request, without sending it.
+func (failure *uploadFailure) replay(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	if failure.err != nil {
+		return nil, failure.err
+	}
+	return &http.Response{Status: failure.status, StatusCode: failure.statusCode, Body: ioutil.NopCloser(bytes.NewReader(nil)), Request: req}, nil
+}
+
+// Tracks the failed attempts of the upload requests by their URLs, and the last failure of each URL.
+// The upload service makes retries+1 attempts of each file, so a URL is forgotten after them, or once it succeeds.
+type uploadAttempts struct {
+	retries        int
+	mutex          sync.Mutex
+	failedAttempts map[string]int
+	lastFailures   map[string]*uploadFailure
+}
+
+func newUploadAttempts(retries int) *uploadAttempts {
+	return &uploadAttempts{retries: retries, failedAttempts: make(map[string]int), lastFailures: make(map[string]*uploadFailure)}
+}
+
+// Returns the failed attempts of the URL, and its last failure.
+func (attempts *uploadAttempts) get(url string) (int, *uploadFailure) {
+	attempts.mutex.Lock()
+	defer attempts.mutex.Unlock()
+	return attempts.failedAttempts[url], attempts.lastFailures[url]
+}
+
+// Records the outcome of an attempt of the URL. Errors and statuses of 500 and above are failures, as retried by the
+// upload service.
+func (attempts *uploadAttempts) record(url string, resp *http.Response, err error) {
+	switch {
+	case err != nil:
+		attempts.addFailure(url, &uploadFailure{err: err})
+	case resp.StatusCode >= 500:
+		attempts.addFailure(url, &uploadFailure{status: resp.Status, statusCode: resp.StatusCode})
+	default:
+		attempts.mutex.Lock()
+		defer attempts.mutex.Unlock()
+		delete(attempts.failedAttempts, url)
+		delete(attempts.lastFailures, url)
+	}
+}
+
+func (attempts *uploadAttempts) addFailure(url string, failure *uploadFailure) {
+	attempts.mutex.Lock()
+	defer attempts.mutex.Unlock()
+	attempts.failedAttempts[url]++
+	attempts.lastFailures[url] = failure
+	if attempts.failedAttempts[url] > attempts.retries {
+		delete(attempts.failedAttempts, url)
+		delete(attempts.lastFailures, url)
+	}
+}
+
+// An http.RoundTripper, which limits the retries of the uploaded files beyond the retries of the upload service.
+// The upload service retries all of the files the same number of times, so the retries of each file are checked
+// against the limits here, and the attempts which exceed them return the last failure of the file without being sent.
+// Refused attempts are counted as failed attempts of the file, so that the file is forgotten once the upload service
+// makes all of its attempts.
+type retriesLimitTransport struct {
+	transport http.RoundTripper
+	attempts  *uploadAttempts
+	// Set only when the retries of each file scale with its size.
+	scaling *retriesScaling
+	// Set only when the total retries of the upload are limited. Shared by the transports of all of the uploaders.
+	budget *retriesBudget
+	// Set only when only some of the statuses are retried.
+	retryOnStatus map[int]bool
+}
+
+func (rt *retriesLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPut || req.Header.Get("X-Checksum-Deploy") == "true" {
+		return rt.transport.RoundTrip(req)
+	}
+	url := req.URL.String()
+	attempt, lastFailure := rt.attempts.get(url)
+	if attempt == 0 && rt.scaling != nil {
+		if retries := rt.scaling.getRetries(req.ContentLength); retries != rt.scaling.baseRetries {
+			log.Info("Uploading", url, "with", strconv.Itoa(retries), "retries, according to its size.")
+		}
+	}
+	if attempt > 0 && !rt.isRetryAllowed(req, attempt, lastFailure) {
+		rt.attempts.addFailure(url, lastFailure)
+		return lastFailure.replay(req)
+	}
+	if attempt > 0 {
+		log.Debug("Retrying", url+". Retry #"+strconv.Itoa(attempt), "of the file.")
+	}
+
+	resp, err := rt.transport.RoundTrip(req)
+	rt.attempts.record(url, resp, err)
+	return resp, err
+}
+
+// Returns true if the retry of the request, following its failed attempts, is within the limits. A retry consumes a
+// retry from the budget only if it is within the other limits.
+func (rt *retriesLimitTransport) isRetryAllowed(req *http.Request, attempt int, lastFailure *uploadFailure) bool {
+	if rt.retryOnStatus != nil && !isRetriedFailure(lastFailure, rt.retryOnStatus) {
+		log.Debug("Not retrying", req.URL.String(), "since its status is not retried:", lastFailure.status)
+		return false
+	}
+	if rt.scaling != nil && attempt > rt.scaling.getRetries(req.ContentLength) {
+		return false
+	}
+	return rt.budget.consume()
+}
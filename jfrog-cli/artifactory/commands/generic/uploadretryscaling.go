@@ -0,0 +1,23 @@
+package generic
+
+// Returns the number of retries of a file of the specified size: the base retries, with an additional retry for each
+// retriesSizeScalingMB of the size, capped at maxRetries.
+func getScaledRetries(baseRetries int, size int64, retriesSizeScalingMB, maxRetries int) int {
+	retries := baseRetries + int(size/(int64(retriesSizeScalingMB)<<20))
+	if retries > maxRetries {
+		return maxRetries
+	}
+	return retries
+}
+
+// Limits the retries of each uploaded file according to its size. The upload service is set to retry up to the
+// maximum retries, and the retries limit transport refuses the attempts of each file, which exceed its own retries.
+type retriesScaling struct {
+	baseRetries          int
+	retriesSizeScalingMB int
+	maxRetries           int
+}
+
+func (scaling *retriesScaling) getRetries(size int64) int {
+	return getScaledRetries(scaling.baseRetries, size, scaling.retriesSizeScalingMB, scaling.maxRetries)
+}
@@ -0,0 +1,41 @@
+package generic
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Returns the statuses as a set.
+func newRetryOnStatusSet(statuses []int) map[int]bool {
+	set := make(map[int]bool, len(statuses))
+	for _, status := range statuses {
+		set[status] = true
+	}
+	return set
+}
+
+// An http.RoundTripper, which fails the upload requests whose retried statuses are below 500 with an error, since
+// the upload service retries only errors and statuses of 500 and above. It wraps the transport sending the requests,
+// so that all of the transports wrapping it handle these statuses as failures.
+type retryStatusErrorTransport struct {
+	transport     http.RoundTripper
+	retryOnStatus map[int]bool
+}
+
+func (rt *retryStatusErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.transport.RoundTrip(req)
+	if err != nil || req.Method != http.MethodPut || req.Header.Get("X-Checksum-Deploy") == "true" {
+		return resp, err
+	}
+	if resp.StatusCode < 500 && rt.retryOnStatus[resp.StatusCode] {
+		resp.Body.Close()
+		return nil, errors.New("Artifactory response: " + resp.Status)
+	}
+	return resp, nil
+}
+
+// Returns true if the failure is retried: errors, including the retried statuses below 500, and the retried statuses of
+// 500 and above.
+func isRetriedFailure(failure *uploadFailure, retryOnStatus map[int]bool) bool {
+	return failure.err != nil || retryOnStatus[failure.statusCode]
+}
@@ -0,0 +1,157 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The wait between the attempts to delete an artifact during a rollback, when the upload has no retry wait.
+const defaultRollbackRetryWait = time.Second
+
+// An http.RoundTripper, which records whether the target paths of the upload already exist in Artifactory before they
+// are first uploaded, so that the rollback of a failed transactional upload does not delete the artifacts which the
+// upload overwrote, or skipped since they already existed. The target paths which cannot be checked are recorded as
+// existing, so that they are kept as well.
+type existingPathsTransport struct {
+	transport http.RoundTripper
+	// The URL path of Artifactory, which is trimmed from the upload URL paths to get the target paths.
+	artifactoryPath string
+	mutex           sync.Mutex
+	// Whether each of the checked target URL paths existed before it was first uploaded.
+	existing map[string]bool
+}
+
+func newExistingPathsTransport(transport http.RoundTripper, artifactoryUrl string) (*existingPathsTransport, error) {
+	parsedUrl, err := url.Parse(artifactoryUrl)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	return &existingPathsTransport{transport: transport, artifactoryPath: parsedUrl.Path, existing: make(map[string]bool)}, nil
+}
+
+func (et *existingPathsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPut {
+		return et.transport.RoundTrip(req)
+	}
+	targetPath := strings.SplitN(req.URL.Path, ";", 2)[0]
+	et.mutex.Lock()
+	_, checked := et.existing[targetPath]
+	et.mutex.Unlock()
+	if !checked {
+		info, err := requestStorageInfo(et.transport, req, et.artifactoryPath, targetPath)
+		if err != nil {
+			log.Debug("Failed checking whether", targetPath, "exists, so it is not rolled back:", err.Error())
+		}
+		et.mutex.Lock()
+		if _, checked = et.existing[targetPath]; !checked {
+			et.existing[targetPath] = err != nil || info != nil
+		}
+		et.mutex.Unlock()
+	}
+	return et.transport.RoundTrip(req)
+}
+
+// Returns true if the target URL path existed before it was uploaded, or if it was not checked.
+func (et *existingPathsTransport) isExisting(targetPath string) bool {
+	et.mutex.Lock()
+	defer et.mutex.Unlock()
+	existing, checked := et.existing[targetPath]
+	return existing || !checked
+}
+
+// Deletes the artifacts uploaded by a failed transactional upload, and adds the outcome of the rollback to the error
+// of the upload. The artifacts whose target paths existed before the upload are kept, since their previous versions
+// cannot be restored, and are reported as not rolled back.
+// Returns the artifacts which are left in Artifactory, and the number of deleted artifacts.
+func rollbackFailedUpload(uploadErr error, filesInfo []clientutils.FileInfo, uploaders []*specUploader, uploadService *services.UploadService, configuration *UploadConfiguration) (remaining []clientutils.FileInfo, rolledBack int) {
+	var toDelete []clientutils.FileInfo
+	var notRolledBack []string
+	for _, fileInfo := range filesInfo {
+		if isExistingByUploaders(fileInfo.ArtifactoryPath, uploaders) {
+			targetPath := getRelativeTargetPath(fileInfo.ArtifactoryPath, uploadService.ArtDetails.GetUrl())
+			log.Warn("Not rolling back the upload of", targetPath+", since it already existed before the upload.")
+			notRolledBack = append(notRolledBack, targetPath)
+			remaining = append(remaining, fileInfo)
+			continue
+		}
+		toDelete = append(toDelete, fileInfo)
+	}
+	var partialErr *UploadPartialError
+	if errors.As(uploadErr, &partialErr) {
+		partialErr.NotRolledBack = notRolledBack
+	}
+	if len(toDelete) == 0 {
+		return
+	}
+	log.Info("Rolling back the transactional upload, by deleting the", strconv.Itoa(len(toDelete)), "artifacts it uploaded...")
+	retryWait := time.Duration(configuration.RetryWaitMilliSecs) * time.Millisecond
+	if retryWait <= 0 {
+		retryWait = defaultRollbackRetryWait
+	}
+	var failedPaths []string
+	for _, fileInfo := range toDelete {
+		targetPath := getRelativeTargetPath(fileInfo.ArtifactoryPath, uploadService.ArtDetails.GetUrl())
+		if err := deleteUploadedArtifact(fileInfo.ArtifactoryPath, getUploadRetries(configuration), retryWait, uploadService, uploaders[0].transports.getRequestRateLimiter()); err != nil {
+			log.Error("Failed rolling back the upload of", targetPath+":", err)
+			failedPaths = append(failedPaths, targetPath)
+			remaining = append(remaining, fileInfo)
+			continue
+		}
+		rolledBack++
+	}
+	if len(failedPaths) > 0 {
+		log.Error("The rollback failed to delete", strconv.Itoa(len(failedPaths)), "of the uploaded artifacts, which are left in Artifactory.")
+	} else {
+		log.Info("Rolled back the upload of", strconv.Itoa(rolledBack), "artifacts.")
+	}
+	if partialErr != nil {
+		partialErr.RolledBack = rolledBack
+		partialErr.RollbackFailed = failedPaths
+	}
+	return
+}
+
+// Returns true if the target path of the artifact existed before it was uploaded by any of the uploaders.
+// The artifacts which none of the uploaders checked are considered existing, so that they are not deleted.
+func isExistingByUploaders(artifactoryPath string, uploaders []*specUploader) bool {
+	targetUrl, err := url.Parse(artifactoryPath)
+	if err != nil {
+		return true
+	}
+	for _, uploader := range uploaders {
+		if uploader.transports.existingPaths != nil && !uploader.transports.existingPaths.isExisting(targetUrl.Path) {
+			return false
+		}
+	}
+	return true
+}
+
+// Deletes the artifact, retrying the deletion up to retries times. An artifact which no longer exists is deleted.
+func deleteUploadedArtifact(artifactoryPath string, retries int, retryWait time.Duration, uploadService *services.UploadService, limiter *requestRateLimiter) (err error) {
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			log.Warn("Retry #"+strconv.Itoa(attempt), "of deleting", artifactoryPath, "in", retryWait.String(), "after failure -", err.Error())
+			sleep(retryWait)
+		}
+		limiter.wait()
+		var resp *http.Response
+		resp, _, err = uploadService.GetJfrogHttpClient().SendDelete(artifactoryPath, nil, uploadService.ArtDetails.CreateHttpClientDetails())
+		if err != nil {
+			continue
+		}
+		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		err = errors.New("Artifactory response: " + resp.Status)
+	}
+	return
+}
@@ -0,0 +1,89 @@
+package generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The extension of the metadata files, rendered from the sidecar template and uploaded next to the artifacts.
+const SidecarExtension = ".metadata.json"
+
+// Reads the sidecar template. The template is rendered separately for each of the uploaded artifacts.
+func readSidecarTemplate(templatePath string) (string, error) {
+	content, err := ioutil.ReadFile(templatePath)
+	if errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Renders the sidecar template of the uploaded artifact. The placeholders of the template, {name}, {localPath}, {target},
+// {sha1}, {md5}, {buildName}, {buildNumber} and {timestamp}, are replaced by the details of the artifact and of the build.
+// The values are escaped as JSON strings, so the placeholders should be placed inside the strings of the template.
+func renderSidecar(template string, artifact clientutils.FileInfo, target string, configuration *UploadConfiguration) ([]byte, error) {
+	var sha1, md5 string
+	if artifact.FileHashes != nil {
+		sha1, md5 = artifact.Sha1, artifact.Md5
+	}
+	content := strings.NewReplacer(
+		"{name}", escapeJsonString(filepath.Base(artifact.LocalPath)),
+		"{localPath}", escapeJsonString(filepath.ToSlash(artifact.LocalPath)),
+		"{target}", escapeJsonString(target),
+		"{sha1}", escapeJsonString(sha1),
+		"{md5}", escapeJsonString(md5),
+		"{buildName}", escapeJsonString(configuration.BuildName),
+		"{buildNumber}", escapeJsonString(configuration.BuildNumber),
+		"{timestamp}", escapeJsonString(configuration.TargetTime.Format(time.RFC3339)),
+	).Replace(template)
+	if !json.Valid([]byte(content)) {
+		return nil, errorutils.CheckError(errors.New("The rendered sidecar template is not a valid JSON"))
+	}
+	return []byte(content), nil
+}
+
+// Returns the value escaped as the content of a JSON string, without the enclosing quotes.
+func escapeJsonString(value string) string {
+	escaped, _ := json.Marshal(value)
+	return string(escaped[1 : len(escaped)-1])
+}
+
+// Uploads the metadata files rendered from the sidecar template, next to the uploaded artifacts.
+// Returns the details of the uploaded metadata files, and the number of metadata files which failed to upload.
+func uploadSidecars(template string, artifacts []clientutils.FileInfo, uploadParams services.UploadParams, uploadService *services.UploadService, configuration *UploadConfiguration, checksums *targetChecksumsCache) (sidecarsInfo []clientutils.FileInfo, failed int) {
+	// The placeholders of the props are resolved for each of the files, the same as for the artifacts themselves.
+	placeholders := make(map[string][]string)
+	if files, err := collectFilesForUpload(uploadParams, checksums); err == nil {
+		for _, file := range files {
+			placeholders[file.localPath] = file.placeholders
+		}
+	}
+	for _, artifact := range artifacts {
+		target := getRelativeTargetPath(artifact.ArtifactoryPath, uploadService.ArtDetails.GetUrl())
+		content, err := renderSidecar(template, artifact, target, configuration)
+		if err != nil {
+			log.Error("Failed rendering the metadata file of", artifact.LocalPath+":", err)
+			failed++
+			continue
+		}
+		sidecarParams := copyUploadParams(uploadParams)
+		sidecarParams.SetTarget(target + SidecarExtension)
+		sidecarParams.SetProps(resolvePlaceholders(uploadParams.GetProps(), placeholders[artifact.LocalPath]))
+		sidecarInfo, err := uploadStream(artifact.LocalPath+SidecarExtension, "the metadata file of "+artifact.LocalPath, bytes.NewReader(content), sidecarParams, uploadService)
+		if err != nil {
+			log.Error("Failed uploading the metadata file of", artifact.LocalPath+":", err)
+			failed++
+			continue
+		}
+		sidecarsInfo = append(sidecarsInfo, sidecarInfo)
+	}
+	return
+}
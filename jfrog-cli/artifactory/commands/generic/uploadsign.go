@@ -0,0 +1,111 @@
+package generic
+
+import (
+	"bytes"
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/crypto/openpgp"
+	"os"
+)
+
+// The extension of the detached ASCII-armored signatures, uploaded next to the signed artifacts.
+const SignatureExtension = ".asc"
+
+// A detached signature of a local file, created before the file is uploaded.
+type signature struct {
+	content []byte
+	// The props of the signed file, which are also attached to its signature.
+	props string
+}
+
+// Reads the private key, used for signing the uploaded artifacts, from an ASCII-armored key file.
+// If the key is encrypted, it is decrypted using the passphrase.
+func readSigningKey(keyPath, passphrase string) (*openpgp.Entity, error) {
+	if keyPath == "" {
+		return nil, errorutils.CheckError(errors.New("A signing key must be provided in order to sign the uploaded artifacts."))
+	}
+	keyFile, err := os.Open(keyPath)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	defer keyFile.Close()
+	entities, err := openpgp.ReadArmoredKeyRing(keyFile)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	for _, entity := range entities {
+		if entity.PrivateKey == nil {
+			continue
+		}
+		if entity.PrivateKey.Encrypted {
+			if err = entity.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+				return nil, errorutils.CheckError(errors.New("Failed decrypting the signing key " + keyPath + ": " + err.Error()))
+			}
+		}
+		return entity, nil
+	}
+	return nil, errorutils.CheckError(errors.New("No private key was found in " + keyPath))
+}
+
+// Signs the files matching the file spec locally, before they are uploaded. The returned signatures are keyed by the
+// local path of the signed file. Uploads from stdin and uploads of archives created on the fly are streamed, and
+// therefore not signed.
+func createSignatures(f *spec.File, uploadParams services.UploadParams, signer *openpgp.Entity, checksums *targetChecksumsCache) (map[string]signature, error) {
+	if isStdinUpload(uploadParams) || f.Archive != "" {
+		log.Warn("Streamed uploads are not signed:", uploadParams.GetPattern())
+		return nil, nil
+	}
+	files, err := collectFilesForUpload(uploadParams, checksums)
+	if err != nil {
+		return nil, err
+	}
+	signatures := make(map[string]signature, len(files))
+	for _, file := range files {
+		if file.isDir || (file.symlink != "" && uploadParams.IsSymlink()) {
+			continue
+		}
+		content, err := signFile(file.localPath, signer)
+		if err != nil {
+			return nil, err
+		}
+		signatures[file.localPath] = signature{content: content, props: resolvePlaceholders(uploadParams.GetProps(), file.placeholders)}
+	}
+	return signatures, nil
+}
+
+func signFile(localPath string, signer *openpgp.Entity) ([]byte, error) {
+	file, err := os.Open(localPath)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	defer file.Close()
+	content := new(bytes.Buffer)
+	err = openpgp.ArmoredDetachSign(content, signer, file, nil)
+	return content.Bytes(), errorutils.CheckError(err)
+}
+
+// Uploads the signatures of the uploaded artifacts, next to the artifacts.
+// Returns the details of the uploaded signatures, and the number of signatures which failed to upload.
+func uploadSignatures(signatures map[string]signature, artifacts []clientutils.FileInfo, uploadParams services.UploadParams, uploadService *services.UploadService) (signaturesInfo []clientutils.FileInfo, failed int) {
+	for _, artifact := range artifacts {
+		sig, ok := signatures[artifact.LocalPath]
+		if !ok {
+			continue
+		}
+		signatureParams := copyUploadParams(uploadParams)
+		signatureParams.SetTarget(getRelativeTargetPath(artifact.ArtifactoryPath, uploadService.ArtDetails.GetUrl()) + SignatureExtension)
+		signatureParams.SetProps(sig.props)
+		signatureInfo, err := uploadStream(artifact.LocalPath+SignatureExtension, "the signature of "+artifact.LocalPath, bytes.NewReader(sig.content), signatureParams, uploadService)
+		if err != nil {
+			log.Error("Failed uploading the signature of", artifact.LocalPath+":", err)
+			failed++
+			continue
+		}
+		signaturesInfo = append(signaturesInfo, signatureInfo)
+	}
+	return
+}
@@ -0,0 +1,124 @@
+package generic
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// An http.RoundTripper, which skips the upload of files already existing in Artifactory with the same checksum at the exact
+// target path. Unlike checksum deploy, which creates a new artifact from existing content, skipped files are not written
+// to Artifactory at all, so their properties are not updated either.
+// Skipped uploads are answered as if they were successful, so that the upload service still returns their details.
+type skipExistingTransport struct {
+	transport http.RoundTripper
+	// The URL path of Artifactory, which is trimmed from the upload URL paths to get the target paths.
+	artifactoryPath string
+	mutex           sync.Mutex
+	// The target URL paths which were checked, so that the upload following a failed checksum deploy is not checked again.
+	checked map[string]bool
+	// The target URL paths of the skipped uploads.
+	skippedPaths map[string]bool
+	skipped      int
+}
+
+func newSkipExistingTransport(transport http.RoundTripper, artifactoryUrl string) (*skipExistingTransport, error) {
+	parsedUrl, err := url.Parse(artifactoryUrl)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	return &skipExistingTransport{transport: transport, artifactoryPath: parsedUrl.Path, checked: make(map[string]bool), skippedPaths: make(map[string]bool)}, nil
+}
+
+func (st *skipExistingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	sha1 := req.Header.Get("X-Checksum-Sha1")
+	if req.Method != http.MethodPut || sha1 == "" || req.Header.Get("X-Explode-Archive") == "true" {
+		return st.transport.RoundTrip(req)
+	}
+	targetPath := strings.SplitN(req.URL.Path, ";", 2)[0]
+	st.mutex.Lock()
+	checked := st.checked[targetPath]
+	st.checked[targetPath] = true
+	st.mutex.Unlock()
+	if checked || !st.isIdentical(req, targetPath, sha1) {
+		return st.transport.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	st.mutex.Lock()
+	st.skipped++
+	st.skippedPaths[targetPath] = true
+	st.mutex.Unlock()
+	log.Info("Skipping the upload of", strings.TrimPrefix(targetPath, st.artifactoryPath), "since it already exists with the same checksum.")
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      req.Proto,
+		ProtoMajor: req.ProtoMajor,
+		ProtoMinor: req.ProtoMinor,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+// Returns true if the target path exists in Artifactory with the specified SHA1 checksum.
+// If the target path cannot be checked, it is uploaded.
+func (st *skipExistingTransport) isIdentical(req *http.Request, targetPath, sha1 string) bool {
+	info, err := requestStorageInfo(st.transport, req, st.artifactoryPath, targetPath)
+	if err != nil {
+		log.Debug("Failed checking whether", targetPath, "exists:", err.Error())
+		return false
+	}
+	return info != nil && info.Checksums.Sha1 == sha1
+}
+
+// Sends a storage info request of the target URL path through the transport, with the authentication headers of the
+// upload request. Returns nil if the target path does not exist.
+func requestStorageInfo(transport http.RoundTripper, req *http.Request, artifactoryPath, targetPath string) (*storageInfo, error) {
+	storageUrl := *req.URL
+	storageUrl.Path = artifactoryPath + "api/storage/" + strings.TrimPrefix(targetPath, artifactoryPath)
+	storageUrl.RawPath = ""
+	storageUrl.RawQuery = ""
+	storageReq, err := http.NewRequest(http.MethodGet, storageUrl.String(), nil)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	// Only the authentication headers of the upload request are relevant.
+	for _, name := range []string{"Authorization", "X-JFrog-Art-Api"} {
+		if value := req.Header.Get(name); value != "" {
+			storageReq.Header.Set(name, value)
+		}
+	}
+	resp, err := transport.RoundTrip(storageReq)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, errorutils.CheckError(errors.New("Artifactory response: " + resp.Status))
+	}
+	info := new(storageInfo)
+	err = json.NewDecoder(resp.Body).Decode(info)
+	return info, errorutils.CheckError(err)
+}
+
+// Returns the number of uploads skipped since the last call.
+func (st *skipExistingTransport) takeSkipped() int {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	skipped := st.skipped
+	st.skipped = 0
+	return skipped
+}
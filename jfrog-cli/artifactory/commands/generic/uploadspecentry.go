@@ -0,0 +1,217 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/cliutils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/crypto/openpgp"
+	"strconv"
+)
+
+// Uploads a single spec file entry, with its signatures, metadata files and empty directory placeholders, by the uploader.
+// The index is the position of the entry in the spec, by which it is reported.
+func uploadSpecEntry(f *spec.File, i int, uploader *specUploader, signer *openpgp.Entity, sidecarTemplate string, servicesManager *artifactory.ArtifactoryServicesManager, configuration *UploadConfiguration) (result specEntryResult) {
+	uploadParams, err := getUploadParams(f, configuration)
+	if err != nil {
+		result.errorOccurred = true
+		log.Error(createUploadErrorRecord(err, f))
+		return
+	}
+
+	checksums := uploader.transports.getTargetChecksumsCache()
+	var existingProps map[string]map[string][]string
+	if configuration.AddProps && !configuration.DryRun {
+		if existingProps, err = getExistingProps(f, uploadParams, uploader.uploadService, checksums); err != nil {
+			result.errorOccurred = true
+			log.Error(createUploadErrorRecord(err, f))
+			return
+		}
+	}
+
+	var signatures map[string]signature
+	if signer != nil {
+		if signatures, err = createSignatures(f, uploadParams, signer, checksums); err != nil {
+			result.errorOccurred = true
+			log.Error(createUploadErrorRecord(err, f))
+			return
+		}
+	}
+
+	artifacts, uploaded, failed, err := uploadSpecFile(f, uploadParams, uploader.uploadService, uploader.transports, configuration)
+	if configuration.PropsAtomic && !configuration.DryRun && err == nil && len(artifacts) > 0 {
+		// The artifacts are counted as failed until their props are fully applied.
+		var propsFailed []clientutils.FileInfo
+		artifacts, propsFailed = applyPropsAtomically(artifacts, uploadParams, uploader.uploadService, servicesManager, uploader.transports.getRequestRateLimiter(), checksums)
+		uploaded -= len(propsFailed)
+		failed += len(propsFailed)
+	}
+	log.Info("File spec entry", strconv.Itoa(i+1), "("+uploadParams.GetPattern()+")", "matched", strconv.Itoa(uploaded+failed), "artifacts.")
+	result.filesInfo = append(result.filesInfo, artifacts...)
+	result.failCount += failed
+	result.successCount += uploaded
+	if uploader.transports.skipExisting != nil {
+		skipped := uploader.transports.skipExisting.takeSkipped()
+		result.successCount -= skipped
+		result.skippedCount += skipped
+	}
+	if failed > 0 || err != nil {
+		failedUploads, failuresErr := getFailedUploads(f, uploadParams, artifacts, checksums)
+		if failuresErr != nil && (isFailuresCollected(configuration) || cliutils.IsJsonLog()) {
+			log.Warn("Failed listing the files which failed to upload:", failuresErr.Error())
+		}
+		logFailedUploads(failedUploads)
+		if isFailuresCollected(configuration) {
+			result.failures = append(result.failures, failedUploads...)
+		}
+		fileErrors := getUploadFileErrors(failedUploads, uploader.transports.status, uploader.uploadService.ArtDetails.GetUrl())
+		if uploader.transports.fileChanges != nil {
+			uploader.transports.fileChanges.setFileErrors(fileErrors)
+		}
+		result.fileErrors = append(result.fileErrors, fileErrors...)
+	}
+	if err != nil {
+		result.errorOccurred = true
+		log.Error(createUploadErrorRecord(err, f))
+		return
+	}
+	if len(signatures) > 0 {
+		signaturesInfo, failedSignatures := uploadSignatures(signatures, artifacts, uploadParams, uploader.uploadService)
+		result.filesInfo = append(result.filesInfo, signaturesInfo...)
+		result.failCount += failedSignatures
+		result.successCount += len(signaturesInfo)
+	}
+	if sidecarTemplate != "" {
+		sidecarsInfo, failedSidecars := uploadSidecars(sidecarTemplate, artifacts, uploadParams, uploader.uploadService, configuration, checksums)
+		result.filesInfo = append(result.filesInfo, sidecarsInfo...)
+		result.failCount += failedSidecars
+		result.successCount += len(sidecarsInfo)
+	}
+	if f.EmptyDirPlaceholder != "" && uploadParams.IsIncludeDirs() {
+		placeholdersInfo, failedPlaceholders, err := uploadEmptyDirPlaceholders(f.EmptyDirPlaceholder, uploadParams, uploader.uploadService, checksums)
+		result.filesInfo = append(result.filesInfo, placeholdersInfo...)
+		result.failCount += failedPlaceholders
+		result.successCount += len(placeholdersInfo)
+		if err != nil {
+			result.errorOccurred = true
+			log.Error(createUploadErrorRecord(err, f))
+		}
+	}
+	uploadedProps := uploadParams.GetProps()
+	addProps(&uploadedProps, getDebianProps(uploadParams.GetDebian()))
+	if err = mergeExistingProps(existingProps, uploadedProps, artifacts, configuration.ArtDetails.Url, servicesManager, uploader.transports.getRequestRateLimiter()); err != nil {
+		result.errorOccurred = true
+		log.Error(createUploadErrorRecord(err, f))
+	}
+	return
+}
+
+// Uploads the files matching a single spec file.
+// If all of the files fail to upload since the target repository is unavailable, the upload is retried with the fallback repositories.
+func uploadSpecFile(f *spec.File, uploadParams services.UploadParams, uploadService *services.UploadService, transports *uploadTransports, configuration *UploadConfiguration) (artifacts []clientutils.FileInfo, uploaded, failed int, err error) {
+	checksums := transports.getTargetChecksumsCache()
+	if isStdinUpload(uploadParams) {
+		// Stdin can be read only once, so the upload cannot fall back to other repositories.
+		return uploadStdinFile(uploadParams, uploadService)
+	}
+	if f.Archive != "" {
+		// The archive is created while it is uploaded, so the upload cannot fall back to other repositories.
+		return uploadArchiveFile(f.Archive, uploadParams, uploadService, isCalcChecksums(configuration))
+	}
+	if isExplodeTargetStructure, _ := f.IsExplodeTargetStructure(false); isExplodeTargetStructure && uploadParams.IsExplodeArchive() {
+		// The archives are extracted while they are uploaded, so the upload cannot fall back to other repositories.
+		return uploadExplodedArchives(uploadParams, uploadService, checksums)
+	}
+	if configuration.FollowSymlinks && !isStdinUpload(uploadParams) {
+		// The upload service would upload the symlinks to the target paths of the files they point to.
+		remainingParams, symlinksInfo, symlinksUploaded, symlinksFailed, symlinksErr := uploadFollowedSymlinks(uploadParams, uploadService, checksums)
+		if symlinksErr != nil {
+			return nil, 0, 0, symlinksErr
+		}
+		defer func() {
+			artifacts = append(symlinksInfo, artifacts...)
+			uploaded += symlinksUploaded
+			failed += symlinksFailed
+		}()
+		if remainingParams == nil {
+			return
+		}
+		uploadParams = *remainingParams
+	}
+	transports.contentType.contentType = f.ContentType
+	transports.contentType.contentEncoding = f.ContentEncoding
+	uploadService.Retries = uploadParams.GetRetries()
+	specThreads, err := f.GetThreads(0)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if specThreads > 0 {
+		defer uploadService.SetThread(uploadService.Threads)
+		uploadService.SetThread(specThreads)
+		log.Debug("Uploading the files of", uploadParams.GetPattern(), "with", strconv.Itoa(specThreads), "threads.")
+	}
+	targets := getFallbackTargets(uploadParams.GetTarget(), configuration.FallbackTargets)
+	originalParams := uploadParams
+	for i, target := range targets {
+		uploadParams = copyUploadParams(originalParams)
+		uploadParams.SetTarget(target)
+		transports.props.props = nil
+		if hasPlaceholders(uploadParams.GetProps()) || transports.props.pathProps != nil || transports.props.extProps != nil {
+			transports.props.props, err = createPlaceholderProps(uploadParams, uploadService.ArtDetails.GetUrl(), transports.props.pathProps, transports.props.extProps, checksums)
+			if err != nil {
+				return
+			}
+		}
+		if transports.multipart != nil {
+			transports.multipart.retries = uploadParams.GetRetries()
+			transports.multipart.files, err = createMultipartFiles(uploadParams, transports.multipart.chunkSize, uploadService.ArtDetails.GetUrl(), checksums)
+			if err != nil {
+				return
+			}
+		}
+		if err = transports.checksumDeploy.setUploadParams(uploadParams, uploadService.ArtDetails.GetUrl(), checksums); err != nil {
+			return
+		}
+		if transports.unchanged != nil {
+			if err = transports.unchanged.setUploadParams(uploadParams, checksums); err != nil {
+				return
+			}
+		}
+		if transports.fileChanges != nil {
+			if err = transports.fileChanges.setUploadParams(uploadParams, checksums); err != nil {
+				return
+			}
+		}
+		if transports.targetChecksums != nil {
+			if err = transports.targetChecksums.setUploadParams(uploadParams); err != nil {
+				return
+			}
+		}
+		transports.status.reset()
+		artifacts, uploaded, failed, err = uploadService.UploadFiles(uploadParams)
+		if transports.targetChecksums != nil {
+			expandArtifactsTargetChecksums(artifacts, checksums)
+		}
+		if transports.unchanged != nil {
+			var skipped int
+			artifacts, skipped = transports.unchanged.removeSkipped(artifacts)
+			uploaded -= skipped
+		}
+		if err != nil || uploaded > 0 || failed == 0 || i == len(targets)-1 || !transports.status.isRepoUnavailable() {
+			return
+		}
+		log.Warn("Failed uploading to", target, "since the repository is unavailable. Retrying with", targets[i+1]+"...")
+	}
+	return
+}
+
+// Same as uploadFromStdin, but returns the results in the form returned by the upload service.
+func uploadStdinFile(uploadParams services.UploadParams, uploadService *services.UploadService) ([]clientutils.FileInfo, int, int, error) {
+	fileInfo, err := uploadFromStdin(uploadParams, uploadService)
+	if err != nil {
+		return nil, 0, 1, err
+	}
+	return []clientutils.FileInfo{fileInfo}, 1, 0, nil
+}
@@ -0,0 +1,46 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"time"
+)
+
+// Adds the props attached to all of the uploaded artifacts to the props of the spec file entries: the props of the
+// target props file, the upload timestamp, the expiry and the VCS props, as configured.
+// Returns the time the artifacts expire, or an empty string if they do not expire.
+func addUploadProps(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration, startTime time.Time) (expiry string, err error) {
+	if configuration.TargetPropsFile != "" {
+		targetProps, err := readPropsFile(configuration.TargetPropsFile)
+		if err != nil {
+			return "", err
+		}
+		addSpecProps(uploadSpec, targetProps)
+	}
+	if configuration.AddUploadTimestampProp {
+		addSpecProps(uploadSpec, UploadTimestampProp+"="+time.Now().Format(time.RFC3339))
+	}
+	if configuration.Expiry > 0 {
+		// All of the artifacts of the upload expire at the same time.
+		expiry = startTime.Add(configuration.Expiry).Format(time.RFC3339)
+		addSpecProps(uploadSpec, ExpiryProp+"="+expiry)
+	}
+	if configuration.AddVcsProps {
+		addSpecProps(uploadSpec, getVcsProps())
+	}
+	return
+}
+
+// Returns a copy of the spec file entries, whose props and targets can be changed by the upload.
+func copySpecFiles(uploadSpec *spec.SpecFiles) *spec.SpecFiles {
+	return &spec.SpecFiles{Files: append([]spec.File(nil), uploadSpec.Files...)}
+}
+
+// Appends the props to the props of each of the spec file entries.
+func addSpecProps(uploadSpec *spec.SpecFiles, props string) {
+	if props == "" {
+		return
+	}
+	for i := 0; i < len(uploadSpec.Files); i++ {
+		addProps(&uploadSpec.Get(i).Props, props)
+	}
+}
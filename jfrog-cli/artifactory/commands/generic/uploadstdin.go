@@ -0,0 +1,149 @@
+package generic
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// A file spec pattern of "-" means that the content to upload is read from stdin.
+const StdinPattern = "-"
+
+// The reader from which stdin uploads read their content. Replaced in tests.
+var stdin io.Reader = os.Stdin
+
+func isStdinUpload(uploadParams services.UploadParams) bool {
+	return uploadParams.GetPattern() == StdinPattern
+}
+
+// Streams the content read from stdin to the exact target path of the upload params, while calculating its checksums.
+// Since stdin can be read only once, the upload is not retried.
+func uploadFromStdin(uploadParams services.UploadParams, uploadService *services.UploadService) (clientutils.FileInfo, error) {
+	return uploadStream(StdinPattern, "stdin", stdin, uploadParams, uploadService)
+}
+
+// Streams the content to the exact target path of the upload params, while calculating its checksums.
+// The local path is the source reported for the uploaded artifact, and the description names the source in messages.
+// In a dry run, the content may be nil, in which case the checksums are not calculated.
+func uploadStream(localPath, description string, content io.Reader, uploadParams services.UploadParams, uploadService *services.UploadService) (clientutils.FileInfo, error) {
+	target := uploadParams.GetTarget()
+	if target == "" || strings.HasSuffix(target, "/") {
+		return clientutils.FileInfo{}, errorutils.CheckError(errors.New("When uploading " + description + ", the target should be a file path, but got: " + target))
+	}
+	artifactoryPath, err := clientutils.BuildArtifactoryUrl(uploadService.ArtDetails.GetUrl(), target, make(map[string]string))
+	if err != nil {
+		return clientutils.FileInfo{}, err
+	}
+	targetUrl, err := addPropsToUrl(artifactoryPath, uploadParams.GetProps(), uploadParams.GetDebian())
+	if err != nil {
+		return clientutils.FileInfo{}, err
+	}
+
+	if content == nil && uploadService.DryRun {
+		log.Info("[Dry run] Uploading "+description+" to:", target)
+		return clientutils.FileInfo{LocalPath: localPath, ArtifactoryPath: artifactoryPath}, nil
+	}
+	sha1Hash, md5Hash, sha256Hash := sha1.New(), md5.New(), sha256.New()
+	content = io.TeeReader(content, io.MultiWriter(sha1Hash, md5Hash, sha256Hash))
+	if uploadService.DryRun {
+		log.Info("[Dry run] Uploading "+description+" to:", target)
+		if _, err = io.Copy(ioutil.Discard, content); errorutils.CheckError(err) != nil {
+			return clientutils.FileInfo{}, err
+		}
+	} else {
+		log.Info("Uploading "+description+" to:", target)
+		if err = putStream(targetUrl, content, uploadService); err != nil {
+			return clientutils.FileInfo{}, err
+		}
+	}
+	return clientutils.FileInfo{
+		LocalPath:       localPath,
+		ArtifactoryPath: artifactoryPath,
+		FileHashes: &clientutils.FileHashes{
+			Sha256: hexSum(sha256Hash),
+			Sha1:   hexSum(sha1Hash),
+			Md5:    hexSum(md5Hash),
+		},
+	}, nil
+}
+
+func putStream(targetUrl string, content io.Reader, uploadService *services.UploadService) error {
+	req, err := http.NewRequest(http.MethodPut, targetUrl, content)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	httpClientDetails := uploadService.ArtDetails.CreateHttpClientDetails()
+	for name, value := range httpClientDetails.Headers {
+		req.Header.Set(name, value)
+	}
+	switch {
+	case httpClientDetails.ApiKey != "" && httpClientDetails.User != "":
+		req.SetBasicAuth(httpClientDetails.User, httpClientDetails.ApiKey)
+	case httpClientDetails.ApiKey != "":
+		req.Header.Set("X-JFrog-Art-Api", httpClientDetails.ApiKey)
+	case httpClientDetails.AccessToken != "" && httpClientDetails.User != "":
+		req.SetBasicAuth(httpClientDetails.User, httpClientDetails.AccessToken)
+	case httpClientDetails.AccessToken != "":
+		req.Header.Set("Authorization", "Bearer "+httpClientDetails.AccessToken)
+	case httpClientDetails.User != "" && httpClientDetails.Password != "":
+		req.SetBasicAuth(httpClientDetails.User, httpClientDetails.Password)
+	}
+	req.Header.Set("User-Agent", utils.GetUserAgent())
+
+	resp, err := uploadService.GetJfrogHttpClient().Client.Do(req)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return errorutils.CheckError(errors.New("Artifactory response: " + resp.Status + "\n" + utils.IndentJson(body)))
+	}
+	return nil
+}
+
+// Adds the properties to the URL as matrix params, the same way the upload service does.
+func addPropsToUrl(url, props, debConfig string) (string, error) {
+	addProps(&props, getDebianProps(debConfig))
+	if props == "" {
+		return url, nil
+	}
+	properties, err := clientutils.ParseProperties(props, clientutils.SplitCommas)
+	if err != nil {
+		return "", err
+	}
+	return url + ";" + properties.ToEncodedString(), nil
+}
+
+// Returns the props, which the upload service attaches to Debian packages, for the specified distribution/component/architecture.
+func getDebianProps(debConfig string) string {
+	if debConfig == "" {
+		return ""
+	}
+	debProps := utils.SplitWithEscape(debConfig, '/')
+	var props []string
+	for i, name := range []string{"deb.distribution", "deb.component", "deb.architecture"} {
+		props = append(props, name+"="+debProps[i])
+	}
+	return strings.Join(props, ";")
+}
+
+func hexSum(hash hash.Hash) string {
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
@@ -0,0 +1,248 @@
+package generic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/summary"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"text/template"
+	"time"
+)
+
+const (
+	uploadStatusOk     = "OK"
+	uploadStatusFailed = "FAILED"
+)
+
+// Returns the files matching the spec file, which were not uploaded.
+// The upload service reports only the number of failed files, so they are found by comparing the matching files to the uploaded artifacts.
+func getFailedUploads(f *spec.File, uploadParams services.UploadParams, artifacts []clientutils.FileInfo, checksums *targetChecksumsCache) ([]UploadResult, error) {
+	files, err := getUploadFiles(f, uploadParams, checksums)
+	if err != nil {
+		return nil, err
+	}
+	uploaded := make(map[string]bool, len(artifacts))
+	for _, artifact := range artifacts {
+		uploaded[artifact.LocalPath] = true
+	}
+	var failures []UploadResult
+	for _, file := range files {
+		if file.isDir || uploaded[file.localPath] {
+			continue
+		}
+		result := UploadResult{LocalPath: file.localPath, TargetPath: file.targetPath}
+		addLocalFileDetails(&result, true)
+		failures = append(failures, result)
+	}
+	return failures, nil
+}
+
+// Writes an aligned table of the uploaded artifacts, followed by the files which failed to upload.
+// Uploaded artifacts which also appear in the failures, such as artifacts which failed verification, are listed only as failed.
+func writeDetailedSummary(writer io.Writer, results, failures []UploadResult) error {
+	failed := make(map[string]bool, len(failures))
+	for _, failure := range failures {
+		failed[failure.LocalPath+"\x00"+failure.TargetPath] = true
+	}
+	tw := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tTARGET\tSIZE\tSHA256\tSTATUS")
+	uploadedCount := 0
+	for _, result := range results {
+		if failed[result.LocalPath+"\x00"+result.TargetPath] {
+			continue
+		}
+		writeDetailedSummaryRow(tw, result, uploadStatusOk)
+		uploadedCount++
+	}
+	for _, failure := range failures {
+		writeDetailedSummaryRow(tw, failure, uploadStatusFailed)
+	}
+	if err := tw.Flush(); errorutils.CheckError(err) != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(writer, "Uploaded:", uploadedCount, "Failed:", len(failures))
+	return errorutils.CheckError(err)
+}
+
+func writeDetailedSummaryRow(writer io.Writer, result UploadResult, status string) {
+	sha256 := result.Sha256
+	if sha256 == "" {
+		sha256 = "-"
+	}
+	fmt.Fprintln(writer, result.LocalPath+"\t"+result.TargetPath+"\t"+strconv.FormatInt(result.Size, 10)+"\t"+sha256+"\t"+status)
+}
+
+// The uploaded artifacts, which were deployed by checksum rather than fully uploaded, and the bytes whose transfer was
+// saved by deploying them by checksum.
+type UploadDeduplication struct {
+	ChecksumDeployed int   `json:"checksumDeployed"`
+	Uploaded         int   `json:"uploaded"`
+	BytesSaved       int64 `json:"bytesSaved"`
+}
+
+func getUploadDeduplication(results []UploadResult) UploadDeduplication {
+	var deduplication UploadDeduplication
+	for _, result := range results {
+		if result.ChecksumDeployed {
+			deduplication.ChecksumDeployed++
+			deduplication.BytesSaved += result.Size
+		} else {
+			deduplication.Uploaded++
+		}
+	}
+	return deduplication
+}
+
+// The summary template, which renders the same summary as the one printed by default at the end of the upload.
+const DefaultUploadSummaryTemplate = `{
+  "status": "{{.Status}}",
+  "totals": {
+    "success": {{.Success}},
+    "failure": {{.Failure}}
+  }
+}
+`
+
+// The data, with which the summary template is executed.
+type UploadSummaryTemplateData struct {
+	// Either success or failure.
+	Status string
+	// The uploaded artifacts.
+	Files []UploadResult
+	// The files which failed to upload.
+	Failures []UploadResult
+	// The number of the uploaded artifacts, including the skipped artifacts.
+	Success int
+	Failure int
+	Skipped int
+	// The total size of the uploaded artifacts.
+	Bytes    int64
+	Duration time.Duration
+	// The error of the upload, or an empty string if it did not fail.
+	Error string
+}
+
+func newUploadSummaryTemplateData(results, failures []UploadResult, successCount, failCount, skippedCount int, duration time.Duration, uploadErr error) *UploadSummaryTemplateData {
+	data := &UploadSummaryTemplateData{
+		Status:   summary.StatusTypes[summary.Success],
+		Files:    results,
+		Failures: failures,
+		Success:  successCount,
+		Failure:  failCount,
+		Skipped:  skippedCount,
+		Duration: duration,
+	}
+	if uploadErr != nil || failCount != 0 {
+		data.Status = summary.StatusTypes[summary.Failure]
+	}
+	if uploadErr != nil {
+		data.Error = uploadErr.Error()
+	}
+	for _, result := range results {
+		data.Bytes += result.Size
+	}
+	return data
+}
+
+func parseSummaryTemplate(text string) (*template.Template, error) {
+	summaryTemplate, err := template.New("summary").Parse(text)
+	if err != nil {
+		return nil, errorutils.CheckError(errors.New("Invalid summary template: " + err.Error()))
+	}
+	return summaryTemplate, nil
+}
+
+func writeTemplateSummary(writer io.Writer, summaryTemplate *template.Template, data *UploadSummaryTemplateData) error {
+	return errorutils.CheckError(summaryTemplate.Execute(writer, data))
+}
+
+// The details of a single uploaded artifact.
+type UploadResult struct {
+	LocalPath  string `json:"localPath"`
+	TargetPath string `json:"targetPath"`
+	Sha256     string `json:"sha256,omitempty"`
+	Sha1       string `json:"sha1,omitempty"`
+	Md5        string `json:"md5,omitempty"`
+	Size       int64  `json:"size"`
+	// The path in which Artifactory stored the artifact, as returned by Artifactory. May differ from the target path,
+	// when the layout of the repository resolves the path. Empty if unknown.
+	ResolvedPath string `json:"resolvedPath,omitempty"`
+	// True if the artifact was deployed by checksum, so that its content was not transferred.
+	ChecksumDeployed bool `json:"checksumDeployed,omitempty"`
+	// True if the upload of the artifact was skipped, since it already existed at its target path with the same
+	// checksum, as configured by UploadConfiguration.SkipExisting.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+type UploadSummary struct {
+	*summary.Summary
+	Files         []UploadResult      `json:"files"`
+	Deduplication UploadDeduplication `json:"deduplication"`
+}
+
+// Converts the artifacts details returned by the upload service to upload results.
+// The target path of each result is relative to the Artifactory URL, in the form of <repository name>/<repository path>.
+// The resolved paths of the results, and whether they were deployed by checksum or skipped, are taken from filesResult.
+// If calcChecksums is not set, the checksums missing from the details are not calculated from the local files.
+func convertFileInfoToUploadResults(filesInfo []clientutils.FileInfo, filesResult *uploadFilesResult, artifactoryUrl string, calcChecksums bool) []UploadResult {
+	results := make([]UploadResult, len(filesInfo))
+	for i, fileInfo := range filesInfo {
+		result := UploadResult{LocalPath: fileInfo.LocalPath, TargetPath: getRelativeTargetPath(fileInfo.ArtifactoryPath, artifactoryUrl)}
+		if targetUrl, err := url.Parse(fileInfo.ArtifactoryPath); err == nil {
+			result.ResolvedPath = filesResult.resolvedPaths[targetUrl.Path]
+			result.ChecksumDeployed = filesResult.checksumDeployed[targetUrl.Path]
+			result.Skipped = filesResult.skipped[targetUrl.Path]
+		}
+		if fileInfo.FileHashes != nil {
+			result.Sha256 = fileInfo.Sha256
+			result.Sha1 = fileInfo.Sha1
+			result.Md5 = fileInfo.Md5
+		}
+		addLocalFileDetails(&result, calcChecksums)
+		results[i] = result
+	}
+	return results
+}
+
+// Adds the size of the local file to the result, and its SHA256 checksum if missing and calcChecksums is set.
+func addLocalFileDetails(result *UploadResult, calcChecksums bool) {
+	if result.LocalPath == StdinPattern {
+		return
+	}
+	if stat, err := os.Lstat(result.LocalPath); err == nil && stat.Mode().IsRegular() {
+		result.Size = stat.Size()
+		// The upload service does not calculate SHA256 checksums, so calculate it here if missing.
+		if result.Sha256 == "" && calcChecksums {
+			result.Sha256, _ = calcSha256(result.LocalPath)
+		}
+	}
+}
+
+func writeUploadSummary(summaryPath string, results []UploadResult, deduplication UploadDeduplication, successCount, failCount int, uploadErr error) error {
+	uploadSummary := &UploadSummary{Summary: summary.New(uploadErr), Files: results, Deduplication: deduplication}
+	uploadSummary.Totals.Success = successCount
+	uploadSummary.Totals.Failure = failCount
+	if uploadErr == nil && failCount != 0 {
+		uploadSummary.Status = summary.Failure
+	}
+	if uploadSummary.Files == nil {
+		uploadSummary.Files = []UploadResult{}
+	}
+	content, err := json.MarshalIndent(uploadSummary, "", "  ")
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	log.Debug("Writing the upload summary to:", summaryPath)
+	return errorutils.CheckError(ioutil.WriteFile(summaryPath, content, 0644))
+}
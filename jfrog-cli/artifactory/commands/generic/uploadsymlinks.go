@@ -0,0 +1,261 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/fspatterns"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// The modes of validating the targets of the uploaded symlinks, when symlinks are preserved.
+// In all modes but SymlinkValidationOff, symlinks with missing targets fail the upload.
+const (
+	// Symlinks with targets outside the upload root path fail the upload.
+	SymlinkValidationStrict = "strict"
+	// Symlinks with targets outside the upload root path are logged as warnings.
+	SymlinkValidationLoose = "loose"
+	// Symlinks are not validated.
+	SymlinkValidationOff = "off"
+)
+
+// The property holding the name of the symlink, whose target's content was uploaded, when symlinks are followed.
+const SymlinkNameProp = "symlink.name"
+
+// Validates the targets of the symlinks matching the upload params, according to the validation mode.
+// Only the validation uses the resolved targets. The symlinks are uploaded with their original targets.
+func validateSymlinks(uploadParams services.UploadParams, validation string) error {
+	if !uploadParams.IsSymlink() || validation == SymlinkValidationOff || isStdinUpload(uploadParams) {
+		return nil
+	}
+	rootPath, err := fspatterns.GetRootPath(utils.ReplaceTildeWithUserHome(uploadParams.GetPattern()), uploadParams.IsRegexp(), uploadParams.IsSymlink())
+	if err != nil {
+		return err
+	}
+	resolvedRootPath, err := resolvePath(rootPath)
+	if err != nil {
+		return err
+	}
+	files, err := collectFilesForUpload(uploadParams, nil)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.symlink == "" {
+			continue
+		}
+		resolvedPath, err := resolvePath(file.localPath)
+		if err != nil {
+			return errorutils.CheckError(errors.New("The symlink " + file.localPath + " points to " + file.symlink + ", which does not exist."))
+		}
+		if isSubPathOf(resolvedPath, resolvedRootPath) {
+			continue
+		}
+		message := "The symlink " + file.localPath + " points to " + file.symlink + ", which is outside of the upload root path " + rootPath + "."
+		if validation == SymlinkValidationStrict {
+			return errorutils.CheckError(errors.New(message))
+		}
+		log.Warn(message)
+	}
+	return nil
+}
+
+// Returns the absolute path, after following all symlinks. Returns an error if the path does not exist.
+func resolvePath(path string) (string, error) {
+	resolvedPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err = os.Stat(resolvedPath); err != nil {
+		return "", err
+	}
+	return filepath.Abs(resolvedPath)
+}
+
+func isSubPathOf(path, rootPath string) bool {
+	return path == rootPath || strings.HasPrefix(path, strings.TrimSuffix(rootPath, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// Makes sure that following the symlinks matching the upload params does not lead to a cycle. A cycle is either a
+// symlink which cannot be resolved since it eventually points to itself, or, when uploading recursively, a symlink to
+// a directory which contains the symlink.
+func validateFollowedSymlinks(uploadParams services.UploadParams) error {
+	if isStdinUpload(uploadParams) {
+		return nil
+	}
+	rootPath, err := fspatterns.GetRootPath(utils.ReplaceTildeWithUserHome(uploadParams.GetPattern()), uploadParams.IsRegexp(), false)
+	if err != nil {
+		return err
+	}
+	if fileutils.IsPathSymlink(rootPath) {
+		if _, err = os.Stat(rootPath); isSymlinkCycle(err) {
+			return errorutils.CheckError(errors.New("The symlink " + rootPath + " is part of a symlink cycle."))
+		}
+	}
+	isDir, err := fileutils.IsDirExists(rootPath, false)
+	if err != nil || !isDir {
+		return err
+	}
+	resolvedRootPath, err := resolvePath(rootPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return validateFollowedSymlinksInDir(rootPath, []string{resolvedRootPath}, uploadParams.IsRecursive())
+}
+
+// Validates the symlinks in the directory, and when recursive, in its subdirectories, including the directories the
+// symlinks point to. The resolved paths of the directory and of its parent directories, as walked into, are provided,
+// so that a symlink pointing to any of them is detected as a cycle.
+func validateFollowedSymlinksInDir(dirPath string, resolvedDirPaths []string, isRecursive bool) error {
+	entries, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		// Unreadable directories are handled by the upload itself.
+		return nil
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dirPath, entry.Name())
+		if entry.Mode()&os.ModeSymlink == 0 {
+			if entry.IsDir() && isRecursive {
+				if err = validateFollowedSymlinksInDir(path, append(resolvedDirPaths, filepath.Join(resolvedDirPaths[len(resolvedDirPaths)-1], entry.Name())), isRecursive); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		target, _ := os.Readlink(path)
+		info, err := os.Stat(path)
+		if isSymlinkCycle(err) {
+			return errorutils.CheckError(errors.New("The symlink " + path + " points to " + target + ", which is part of a symlink cycle."))
+		}
+		if err != nil || !info.IsDir() || !isRecursive {
+			// Symlinks with missing targets fail the upload of the symlinks themselves.
+			continue
+		}
+		resolvedPath, err := resolvePath(path)
+		if err != nil {
+			continue
+		}
+		for _, resolvedDirPath := range resolvedDirPaths {
+			if resolvedPath == resolvedDirPath {
+				return errorutils.CheckError(errors.New("The symlink " + path + " points to " + target + ", which contains the symlink. Following it would create a symlink cycle."))
+			}
+		}
+		if err = validateFollowedSymlinksInDir(path, append(resolvedDirPaths, resolvedPath), isRecursive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns true if the error was returned since the path could not be resolved, due to a symlink cycle.
+func isSymlinkCycle(err error) bool {
+	pathErr, ok := err.(*os.PathError)
+	return ok && pathErr.Err == syscall.ELOOP
+}
+
+// Uploads the content of the files the symlinks matching the upload params point to, to the target paths of the
+// symlinks, with the SymlinkNameProp property. Returns the upload params of the rest of the matching files, which
+// exclude the symlinks, or nil if only symlinks match the upload params.
+func uploadFollowedSymlinks(uploadParams services.UploadParams, uploadService *services.UploadService, checksums *targetChecksumsCache) (remainingParams *services.UploadParams, artifacts []clientutils.FileInfo, uploaded, failed int, err error) {
+	files, err := collectFilesForUpload(uploadParams, checksums)
+	if err != nil {
+		return
+	}
+	if len(files) == 1 && files[0].symlink != "" && files[0].localPath == files[0].symlink {
+		// A single symlink, uploaded to a target directory, is listed by the path it points to rather than by its own path.
+		if files[0].localPath, err = fspatterns.GetRootPath(utils.ReplaceTildeWithUserHome(uploadParams.GetPattern()), uploadParams.IsRegexp(), false); err != nil {
+			return
+		}
+	}
+	target := uploadParams.GetTarget()
+	if strings.Index(target, "/") < 0 {
+		target += "/"
+	}
+	var symlinksCount int
+	excludedParams := copyUploadParams(uploadParams)
+	excludedParams.ExcludePatterns = append([]string{}, uploadParams.ExcludePatterns...)
+	for _, file := range files {
+		if file.isDir || file.symlink == "" {
+			continue
+		}
+		symlinksCount++
+		excludedParams.ExcludePatterns = append(excludedParams.ExcludePatterns, getExactExcludePattern(file.localPath, uploadParams.IsRegexp()))
+		symlinkParams := copyUploadParams(uploadParams)
+		symlinkParams.SetTarget(getUploadTarget(file.localPath, resolvePlaceholders(target, file.placeholders), uploadParams.IsFlat()))
+		props := resolvePlaceholders(uploadParams.GetProps(), file.placeholders)
+		addProps(&props, SymlinkNameProp+"="+filepath.Base(file.localPath))
+		symlinkParams.SetProps(props)
+		symlinkInfo, err := uploadFollowedSymlink(file.localPath, symlinkParams, uploadService)
+		if err != nil {
+			log.Error("Failed uploading the content of the symlink", file.localPath+":", err)
+			failed++
+			continue
+		}
+		artifacts = append(artifacts, symlinkInfo)
+		uploaded++
+	}
+	if symlinksCount < len(files) {
+		remainingParams = &excludedParams
+	}
+	return
+}
+
+func uploadFollowedSymlink(symlinkPath string, uploadParams services.UploadParams, uploadService *services.UploadService) (clientutils.FileInfo, error) {
+	file, err := os.Open(symlinkPath)
+	if errorutils.CheckError(err) != nil {
+		return clientutils.FileInfo{}, err
+	}
+	defer file.Close()
+	return uploadStream(symlinkPath, "the content of the symlink "+symlinkPath, file, uploadParams, uploadService)
+}
+
+// Returns an exclude pattern, which matches exactly the path, according to the type of the patterns of the upload.
+// The wildcard patterns are converted to regular expressions by the upload service, which escapes only some of the
+// special characters, so the rest of them are escaped here.
+func getExactExcludePattern(path string, isRegexp bool) string {
+	var pattern strings.Builder
+	for _, char := range path {
+		switch {
+		case strings.ContainsRune(".^$+", char) && !isRegexp:
+			pattern.WriteRune(char)
+		case char == '*' && !isRegexp:
+			pattern.WriteString(`\x2a`)
+		case strings.ContainsRune(`\.^$+*?()|[]{}`, char):
+			pattern.WriteString(`\` + string(char))
+		default:
+			pattern.WriteRune(char)
+		}
+	}
+	if isRegexp {
+		return "^" + pattern.String() + "$"
+	}
+	return pattern.String()
+}
+
+// Validates the symlinks matched by the spec file entries, according to whether they are preserved or followed.
+func validateSpecSymlinks(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) error {
+	for i := 0; i < len(uploadSpec.Files); i++ {
+		uploadParams, err := getUploadParams(uploadSpec.Get(i), configuration)
+		if err != nil {
+			return err
+		}
+		if configuration.FollowSymlinks {
+			err = validateFollowedSymlinks(uploadParams)
+		} else {
+			err = validateSymlinks(uploadParams, configuration.SymlinkValidation)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"strconv"
+	"strings"
+)
+
+// Deletes the artifacts under the specified path in Artifactory, which were not uploaded by the current upload command.
+func syncDeletes(syncDeletesPath string, filesInfo []clientutils.FileInfo, artifactoryUrl string, servicesManager *artifactory.ArtifactoryServicesManager) error {
+	log.Info("Searching for artifacts to delete under", syncDeletesPath+"...")
+	uploadedPaths := make(map[string]bool, len(filesInfo))
+	for _, fileInfo := range filesInfo {
+		uploadedPaths[getRelativeTargetPath(fileInfo.ArtifactoryPath, artifactoryUrl)] = true
+	}
+	searchParams := services.NewSearchParams()
+	searchParams.ArtifactoryCommonParams = &clientutils.ArtifactoryCommonParams{Pattern: strings.TrimSuffix(syncDeletesPath, "/") + "/*", Recursive: true}
+	resultItems, err := servicesManager.SearchFiles(searchParams)
+	if err != nil {
+		return err
+	}
+	var itemsToDelete []clientutils.ResultItem
+	for _, item := range resultItems {
+		if !uploadedPaths[item.GetItemRelativePath()] {
+			itemsToDelete = append(itemsToDelete, item)
+		}
+	}
+	if len(itemsToDelete) == 0 {
+		return nil
+	}
+	deletedCount, err := servicesManager.DeleteFiles(itemsToDelete)
+	if err != nil {
+		return err
+	}
+	if deletedCount < len(itemsToDelete) {
+		return errorutils.CheckError(errors.New("Failed deleting " + strconv.Itoa(len(itemsToDelete)-deletedCount) + " artifacts under " + syncDeletesPath))
+	}
+	return nil
+}
+
+func logSyncDeletesSkipped(syncDeletesPath string) {
+	if syncDeletesPath != "" {
+		log.Warn("Skipping the deletion of artifacts under", syncDeletesPath, "since the upload did not complete successfully.")
+	}
+}
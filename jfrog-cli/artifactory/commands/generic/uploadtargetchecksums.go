@@ -0,0 +1,203 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The checksum placeholders of the upload target, which are replaced by the checksums of each of the uploaded files.
+var targetChecksumTokens = []string{"{sha256}", "{sha1}", "{md5}"}
+
+// Returns true if the target includes any of the checksum placeholders.
+func hasTargetChecksumTokens(target string) bool {
+	for _, token := range targetChecksumTokens {
+		if strings.Contains(target, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the target of any of the spec file entries has checksum placeholders.
+func hasSpecTargetChecksumTokens(uploadSpec *spec.SpecFiles) bool {
+	for i := 0; i < len(uploadSpec.Files); i++ {
+		if hasTargetChecksumTokens(uploadSpec.Get(i).Target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Wraps the transports of the uploaders with the transports expanding the checksum placeholders of the targets.
+// The uploaders share the checksums cache of the upload.
+func wrapTargetChecksumsTransports(uploaders []*specUploader, checksums *targetChecksumsCache) {
+	for _, uploader := range uploaders {
+		httpClient := uploader.uploadService.GetJfrogHttpClient().Client
+		uploader.transports.targetChecksums = &targetChecksumsTransport{transport: getTransport(httpClient), checksums: checksums}
+		httpClient.Transport = uploader.transports.targetChecksums
+	}
+}
+
+// Replaces the checksum placeholders of the target with the checksums.
+func expandTargetChecksumTokens(target string, checksum fileutils.ChecksumDetails) string {
+	return strings.NewReplacer("{sha256}", checksum.Sha256, "{sha1}", checksum.Sha1, "{md5}", checksum.Md5).Replace(target)
+}
+
+// The checksums of a local file, along with the size and modification time of the file when they were calculated.
+type cachedChecksums struct {
+	size     int64
+	modTime  time.Time
+	checksum fileutils.ChecksumDetails
+}
+
+// The checksums of the local files, whose targets have checksum placeholders, keyed by local path.
+// The files of the upload are collected several times during the upload, so their checksums are calculated once.
+// The upload service does not calculate SHA-256 checksums, so they are also kept by the SHA-1 checksums of the files.
+// A cache is created for each upload, and a nil cache calculates the checksums every time.
+type targetChecksumsCache struct {
+	mutex        sync.Mutex
+	checksums    map[string]cachedChecksums
+	sha256BySha1 map[string]string
+}
+
+func newTargetChecksumsCache() *targetChecksumsCache {
+	return &targetChecksumsCache{checksums: make(map[string]cachedChecksums), sha256BySha1: make(map[string]string)}
+}
+
+// Returns the checksums of the local file, calculating them only if the file changed since they were last calculated.
+func (cache *targetChecksumsCache) get(localPath string) (fileutils.ChecksumDetails, error) {
+	stat, err := os.Stat(localPath)
+	if errorutils.CheckError(err) != nil {
+		return fileutils.ChecksumDetails{}, err
+	}
+	if cache != nil {
+		cache.mutex.Lock()
+		cached, ok := cache.checksums[localPath]
+		cache.mutex.Unlock()
+		if ok && cached.size == stat.Size() && cached.modTime.Equal(stat.ModTime()) {
+			return cached.checksum, nil
+		}
+	}
+	details, err := fileutils.GetFileDetails(localPath)
+	if err != nil {
+		return fileutils.ChecksumDetails{}, err
+	}
+	if details.Checksum.Sha256 == "" {
+		if details.Checksum.Sha256, err = calcSha256(localPath); err != nil {
+			return fileutils.ChecksumDetails{}, err
+		}
+	}
+	if cache != nil {
+		cache.mutex.Lock()
+		cache.checksums[localPath] = cachedChecksums{size: stat.Size(), modTime: stat.ModTime(), checksum: details.Checksum}
+		cache.sha256BySha1[details.Checksum.Sha1] = details.Checksum.Sha256
+		cache.mutex.Unlock()
+	}
+	return details.Checksum, nil
+}
+
+// Adds the SHA-256 checksum to the checksums sent or returned by the upload service, if it is missing.
+// The SHA-256 checksum is known only for the files whose checksums were calculated by the cache.
+func (cache *targetChecksumsCache) complete(checksum fileutils.ChecksumDetails) fileutils.ChecksumDetails {
+	if checksum.Sha256 == "" && cache != nil {
+		cache.mutex.Lock()
+		checksum.Sha256 = cache.sha256BySha1[checksum.Sha1]
+		cache.mutex.Unlock()
+	}
+	return checksum
+}
+
+// Replaces the checksum placeholders in the target paths of the files with the checksums of the files.
+// The target paths of the directories are left as is.
+func expandFilesTargetChecksums(files []uploadFile, checksums *targetChecksumsCache) error {
+	for i := range files {
+		if files[i].isDir || !hasTargetChecksumTokens(files[i].targetPath) {
+			continue
+		}
+		checksum, err := checksums.get(files[i].localPath)
+		if err != nil {
+			return err
+		}
+		files[i].targetPath = expandTargetChecksumTokens(files[i].targetPath, checksum)
+	}
+	return nil
+}
+
+// Replaces the checksum placeholders in the URLs of the uploaded artifacts, as returned by the upload service,
+// with the checksums of the artifacts. The placeholders are escaped in the URLs.
+func expandArtifactsTargetChecksums(artifacts []clientutils.FileInfo, checksums *targetChecksumsCache) {
+	for i := range artifacts {
+		if artifacts[i].FileHashes == nil {
+			continue
+		}
+		artifactoryPath, err := url.PathUnescape(artifacts[i].ArtifactoryPath)
+		if err != nil || !hasTargetChecksumTokens(artifactoryPath) {
+			continue
+		}
+		checksum := checksums.complete(fileutils.ChecksumDetails{Sha256: artifacts[i].Sha256, Sha1: artifacts[i].Sha1, Md5: artifacts[i].Md5})
+		artifacts[i].ArtifactoryPath = expandTargetChecksumTokens(artifactoryPath, checksum)
+	}
+}
+
+// Returns an error if any of the spec file entries, whose targets have checksum placeholders, is uploaded without a
+// local file whose checksums can be calculated before it is uploaded: from stdin, as an archive, or as a symlink.
+func validateTargetChecksumTokens(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) error {
+	for i := 0; i < len(uploadSpec.Files); i++ {
+		f := uploadSpec.Get(i)
+		if !hasTargetChecksumTokens(f.Target) {
+			continue
+		}
+		if f.Pattern == StdinPattern || f.Archive != "" || configuration.Symlink {
+			return errorutils.CheckError(errors.New("File spec entry " + strconv.Itoa(i+1) + ": The {sha256}, {sha1} and {md5} placeholders of the target cannot be used with stdin, archive or symlinks."))
+		}
+	}
+	return nil
+}
+
+// An http.RoundTripper, which replaces the checksum placeholders in the targets of the upload requests with the
+// checksums of the uploaded files, as sent by the upload service in the checksum headers of the requests.
+// The SHA-256 checksums of the files are calculated before they are uploaded, since the upload service does not send them.
+// It wraps all of the other transports, so that they handle the final targets.
+type targetChecksumsTransport struct {
+	transport http.RoundTripper
+	checksums *targetChecksumsCache
+}
+
+func (tct *targetChecksumsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	pathParts := strings.SplitN(req.URL.Path, ";", 2)
+	if req.Method != http.MethodPut || !hasTargetChecksumTokens(pathParts[0]) {
+		return tct.transport.RoundTrip(req)
+	}
+	checksum := tct.checksums.complete(fileutils.ChecksumDetails{Sha256: req.Header.Get("X-Checksum"), Sha1: req.Header.Get("X-Checksum-Sha1"), Md5: req.Header.Get("X-Checksum-Md5")})
+	targetUrl := *req.URL
+	pathParts[0] = expandTargetChecksumTokens(pathParts[0], checksum)
+	targetUrl.Path = strings.Join(pathParts, ";")
+	if targetUrl.RawPath != "" {
+		rawPathParts := strings.SplitN(targetUrl.RawPath, ";", 2)
+		rawPathParts[0] = (&url.URL{Path: pathParts[0]}).EscapedPath()
+		targetUrl.RawPath = strings.Join(rawPathParts, ";")
+	}
+	targetReq := *req
+	targetReq.URL = &targetUrl
+	return tct.transport.RoundTrip(&targetReq)
+}
+
+// Calculates the checksums of the files of the upload params, before they are uploaded.
+func (tct *targetChecksumsTransport) setUploadParams(uploadParams services.UploadParams) error {
+	if !hasTargetChecksumTokens(uploadParams.GetTarget()) {
+		return nil
+	}
+	_, err := collectFilesForUpload(uploadParams, tct.checksums)
+	return err
+}
@@ -0,0 +1,22 @@
+package generic
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expands the date placeholders of the upload target, {year}, {month}, {day} and {epoch}, according to the specified time.
+// The placeholders are expanded before the target is passed to the upload service, which expands the {1}, {2}...
+// placeholders of the pattern's capture groups.
+func expandTargetDateTokens(target string, t time.Time) string {
+	if !strings.Contains(target, "{") {
+		return target
+	}
+	return strings.NewReplacer(
+		"{year}", t.Format("2006"),
+		"{month}", t.Format("01"),
+		"{day}", t.Format("02"),
+		"{epoch}", strconv.FormatInt(t.Unix(), 10),
+	).Replace(target)
+}
@@ -0,0 +1,37 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"runtime"
+	"strconv"
+)
+
+// The maximum number of threads used when the number of threads is scaled automatically.
+const maxAutoThreads = 16
+
+// Returns the number of threads to use when UploadConfiguration.Threads is 0, for the specified number of files.
+// Uploads are I/O bound, so twice the number of CPUs is used, but never more threads than files.
+func getAutoThreadsCount(filesCount int) int {
+	threads := runtime.NumCPU() * 2
+	if threads > maxAutoThreads {
+		threads = maxAutoThreads
+	}
+	if threads > filesCount {
+		threads = filesCount
+	}
+	if threads < 1 {
+		threads = 1
+	}
+	return threads
+}
+
+// Returns the number of threads uploading the files of each spec file entry. If configuration.Threads is 0, the number
+// of threads is derived from the number of files.
+func getUploadThreads(filesCount int, configuration *UploadConfiguration) int {
+	threads := configuration.Threads
+	if threads == 0 {
+		threads = getAutoThreadsCount(filesCount)
+		log.Info("Uploading with", strconv.Itoa(threads), "threads.")
+	}
+	return threads
+}
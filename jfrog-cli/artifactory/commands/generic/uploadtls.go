@@ -0,0 +1,104 @@
+package generic
+
+import (
+	"crypto/tls"
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/auth/cert"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"net/http"
+	"strings"
+)
+
+// Returns the transport of the http client, so that it can be configured.
+// If the http client uses the default transport, it is replaced with a copy of the default transport.
+func getHttpTransport(httpClient *http.Client) *http.Transport {
+	if transport, ok := httpClient.Transport.(*http.Transport); ok {
+		return transport
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	httpClient.Transport = transport
+	return transport
+}
+
+// Sets the proxy, the client certificate and the verification of the server's TLS certificate of the transport, as
+// configured for the upload.
+func setUploadTransportSecurity(transport *http.Transport, artDetails *config.ArtifactoryDetails, configuration *UploadConfiguration) error {
+	if err := setUploadProxy(transport, artDetails.Proxy); err != nil {
+		return err
+	}
+	if err := setUploadClientCert(transport, artDetails.ClientCertPath, artDetails.ClientCertKeyPath); err != nil {
+		return err
+	}
+	if configuration.InsecureTls {
+		setUploadInsecureTls(transport)
+	}
+	return nil
+}
+
+// Returns the transport of the OIDC token exchange, with the certificates of the security directory, as used by the
+// upload service, and with the same proxy, client certificate and TLS settings as the upload requests.
+func createOidcTransport(artDetails *config.ArtifactoryDetails, configuration *UploadConfiguration) (http.RoundTripper, error) {
+	certPath, err := utils.GetJfrogSecurityDir()
+	if err != nil {
+		return nil, err
+	}
+	transport, err := cert.GetTransportWithLoadedCert(certPath)
+	if err != nil {
+		return nil, err
+	}
+	if err = setUploadTransportSecurity(transport, artDetails, configuration); err != nil {
+		return nil, err
+	}
+	return &clientCertHintTransport{transport: transport, hasClientCert: artDetails.ClientCertPath != ""}, nil
+}
+
+// Sets the client certificate of the upload service's transport, for servers requiring mutual TLS authentication.
+func setUploadClientCert(transport *http.Transport, certPath, keyPath string) error {
+	if certPath == "" && keyPath == "" {
+		return nil
+	}
+	if certPath == "" || keyPath == "" {
+		return errorutils.CheckError(errors.New("Both the client certificate and its private key should be provided for client certificate authentication."))
+	}
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return errorutils.CheckError(errors.New("Failed loading the client certificate " + certPath + ": " + err.Error()))
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.Certificates = append(transport.TLSClientConfig.Certificates, cert)
+	return nil
+}
+
+// Disables the verification of the server's TLS certificate by the upload service's transport.
+func setUploadInsecureTls(transport *http.Transport) {
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.InsecureSkipVerify = true
+}
+
+// An http.RoundTripper, which adds a hint to the TLS errors returned when the server requires a client certificate.
+type clientCertHintTransport struct {
+	transport     http.RoundTripper
+	hasClientCert bool
+}
+
+func (ct *clientCertHintTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := ct.transport.RoundTrip(req)
+	if err != nil && isClientCertError(err) {
+		if ct.hasClientCert {
+			return resp, errorutils.CheckError(errors.New(err.Error() + ". The server rejected the client certificate."))
+		}
+		return resp, errorutils.CheckError(errors.New(err.Error() + ". The server may require a client certificate, which can be provided using the --client-cert-path and --client-cert-key-path options."))
+	}
+	return resp, err
+}
+
+func isClientCertError(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, "tls: certificate required") || strings.Contains(message, "tls: bad certificate")
+}
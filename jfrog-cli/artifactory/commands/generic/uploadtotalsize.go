@@ -0,0 +1,41 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"sort"
+	"strconv"
+)
+
+// The number of the largest files, which are listed when the maximum total size is exceeded.
+const maxTotalSizeLargestFiles = 10
+
+// Returns an error if the total size of the planned uploads exceeds the maximum total size in MB.
+// The error lists the largest of the files, to help narrow down the upload.
+func validateTotalSize(plannedUploads []DryRunUpload, maxTotalSizeMB int) error {
+	maxTotalSize := int64(maxTotalSizeMB) << 20
+	var totalSize int64
+	var files []DryRunUpload
+	for _, plannedUpload := range plannedUploads {
+		if !plannedUpload.isLocalFile {
+			// Directories, and sources which are not local files, such as stdin, are not counted.
+			continue
+		}
+		totalSize += plannedUpload.size
+		files = append(files, plannedUpload)
+	}
+	if totalSize <= maxTotalSize {
+		return nil
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].size > files[j].size
+	})
+	if len(files) > maxTotalSizeLargestFiles {
+		files = files[:maxTotalSizeLargestFiles]
+	}
+	message := "The total size of the files to upload, " + formatBytes(totalSize) + ", exceeds the maximum total size of " + strconv.Itoa(maxTotalSizeMB) + " MB. The largest files are:"
+	for _, file := range files {
+		message += "\n  " + formatBytes(file.size) + "\t" + file.Source + " -> " + file.Target
+	}
+	return errorutils.CheckError(errors.New(message))
+}
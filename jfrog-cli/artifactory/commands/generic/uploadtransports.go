@@ -0,0 +1,206 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/http"
+)
+
+func createUploadServiceConfig(artDetails *config.ArtifactoryDetails, flags *UploadConfiguration, threads int) (artifactory.Config, error) {
+	if flags.InsecureTls {
+		log.Warn("INSECURE: The TLS certificate of the Artifactory server is not verified, since the --insecure-tls option is used. Use it only for testing against ephemeral servers.")
+	}
+	if artDetails.OidcProvider != "" && artDetails.OidcTransport == nil {
+		oidcTransport, err := createOidcTransport(artDetails, flags)
+		if err != nil {
+			return nil, err
+		}
+		oidcDetails := *artDetails
+		oidcDetails.OidcTransport = oidcTransport
+		artDetails = &oidcDetails
+	}
+	return utils.NewServicesConfig(artDetails,
+		utils.WithDryRun(flags.DryRun),
+		utils.WithMinChecksumDeploySize(flags.MinChecksumDeploySize),
+		utils.WithThreads(threads))
+}
+
+// Creates the upload service directly, rather than through the services manager,
+// so that the transport of its http client can be wrapped.
+// The services config does not support proxies, client certificates and timeouts, so these are set directly on the transport.
+func createUploadService(servicesConfig artifactory.Config, artDetails *config.ArtifactoryDetails, configuration *UploadConfiguration) (*services.UploadService, error) {
+	httpClient, err := artifactory.CreateArtifactoryHttpClient(servicesConfig)
+	if err != nil {
+		return nil, err
+	}
+	transport := getHttpTransport(httpClient.Client)
+	if err = setUploadTransportSecurity(transport, artDetails, configuration); err != nil {
+		return nil, err
+	}
+	if artDetails.Proxy != "" {
+		log.Info("Uploading through the proxy:", redactProxy(artDetails.Proxy))
+	}
+	setUploadConnection(transport, configuration.ConnectionTimeout, configuration.KeepAlive, servicesConfig.GetThreads())
+	httpClient.Client.Transport = &clientCertHintTransport{transport: transport, hasClientCert: artDetails.ClientCertPath != ""}
+	uploadService := services.NewUploadService(httpClient)
+	uploadService.SetThread(servicesConfig.GetThreads())
+	uploadService.SetArtDetails(servicesConfig.GetArtDetails())
+	uploadService.SetDryRun(servicesConfig.IsDryRun())
+	uploadService.MinChecksumDeploy = servicesConfig.GetMinChecksumDeploy()
+	return uploadService, nil
+}
+
+// The transports wrapping the http client of the upload service, which control the upload requests per spec file.
+type uploadTransports struct {
+	props          *placeholderPropsTransport
+	contentType    *contentTypeTransport
+	status         *statusTransport
+	checksumDeploy *checksumDeployTransport
+	// Set only when large files are uploaded in parts.
+	multipart *multipartTransport
+	// Set only when the retries of the files are limited beyond the retries of the upload service.
+	retriesLimit *retriesLimitTransport
+	// Set only when existing files are skipped.
+	skipExisting *skipExistingTransport
+	// Set only when the upload is transactional.
+	existingPaths *existingPathsTransport
+	// Set only when the unchanged files are skipped.
+	unchanged *unchangedFilesTransport
+	// Set only when the target of any of the spec files has checksum placeholders.
+	targetChecksums *targetChecksumsTransport
+	// Set only when the rate of the requests is limited.
+	requestRate *requestRateLimitTransport
+	// Set only when the files which change during the upload fail to upload.
+	fileChanges *fileChangesTransport
+}
+
+// Returns the limiter of the rate of the requests, or nil if the rate is not limited.
+func (transports *uploadTransports) getRequestRateLimiter() *requestRateLimiter {
+	if transports.requestRate == nil {
+		return nil
+	}
+	return transports.requestRate.limiter
+}
+
+// Returns the budget of the total retries of the upload, or nil if the total retries are not limited.
+func (transports *uploadTransports) getRetriesBudget() *retriesBudget {
+	if transports.retriesLimit == nil {
+		return nil
+	}
+	return transports.retriesLimit.budget
+}
+
+// Returns the filter of the unchanged files, or nil if all of the files are uploaded.
+func (transports *uploadTransports) getChangedFilesFilter() *changedFilesFilter {
+	if transports.unchanged == nil {
+		return nil
+	}
+	return transports.unchanged.filter
+}
+
+// Returns the checksums cache of the upload, or nil if the targets have no checksum placeholders.
+func (transports *uploadTransports) getTargetChecksumsCache() *targetChecksumsCache {
+	if transports.targetChecksums == nil {
+		return nil
+	}
+	return transports.targetChecksums.checksums
+}
+
+// Wraps the transport of the upload service's http client with the transports controlling the upload requests.
+// If the changed files filter is set, the unchanged files are skipped.
+func wrapUploadTransport(uploadService *services.UploadService, changedFilter *changedFilesFilter, configuration *UploadConfiguration) (*uploadTransports, error) {
+	httpClient := uploadService.GetJfrogHttpClient().Client
+	transport := getTransport(httpClient)
+	if configuration.UserAgentSuffix != "" {
+		transport = &userAgentTransport{transport: transport, suffix: configuration.UserAgentSuffix}
+	}
+	var requestRate *requestRateLimitTransport
+	if configuration.MaxRequestsPerSec > 0 {
+		requestRate = &requestRateLimitTransport{transport: transport, limiter: newRequestRateLimiter(configuration.MaxRequestsPerSec)}
+		transport = requestRate
+	}
+	var multipart *multipartTransport
+	if configuration.ChunkSizeMB > 0 {
+		splitCount := getMultipartSplitCount(configuration.SplitCount, uploadService.Threads)
+		multipart = &multipartTransport{transport: transport, artifactoryUrl: uploadService.ArtDetails.GetUrl(), chunkSize: int64(configuration.ChunkSizeMB) << 20, splitCount: splitCount, resume: newInMemoryUploadResumeState()}
+		transport = multipart
+	}
+	debConfig, err := getDebConfig(configuration)
+	if err != nil {
+		return nil, err
+	}
+	var retryOnStatus map[int]bool
+	if len(configuration.RetryOnStatus) > 0 {
+		retryOnStatus = newRetryOnStatusSet(configuration.RetryOnStatus)
+		transport = &retryStatusErrorTransport{transport: transport, retryOnStatus: retryOnStatus}
+	}
+	transports := &uploadTransports{status: newStatusTransport(transport), multipart: multipart, requestRate: requestRate}
+	pathProps, err := parsePathPropsTemplate(configuration.PathToProps)
+	if err != nil {
+		return nil, err
+	}
+	extProps, err := parseExtPropsMapping(configuration.ExtProps)
+	if err != nil {
+		return nil, err
+	}
+	transports.props = &placeholderPropsTransport{transport: transports.status, debConfig: debConfig, pathProps: pathProps, extProps: extProps}
+	transports.contentType = &contentTypeTransport{transport: transports.props}
+	if err = validateNoChecksumDeployPatterns(configuration.NoChecksumDeployPatterns); err != nil {
+		return nil, err
+	}
+	transports.checksumDeploy = &checksumDeployTransport{transport: transports.contentType, algorithm: configuration.ChecksumAlgorithm, noChecksumDeployPatterns: configuration.NoChecksumDeployPatterns, checksumOnly: configuration.ChecksumOnlyDeploy}
+	httpClient.Transport = transports.checksumDeploy
+	if !configuration.IgnoreFileChanges && !configuration.DryRun {
+		transports.fileChanges = &fileChangesTransport{transport: httpClient.Transport, artifactoryUrl: uploadService.ArtDetails.GetUrl()}
+		httpClient.Transport = transports.fileChanges
+	}
+	if configuration.SkipExisting && !configuration.DryRun {
+		var err error
+		if transports.skipExisting, err = newSkipExistingTransport(httpClient.Transport, uploadService.ArtDetails.GetUrl()); err != nil {
+			return nil, err
+		}
+		httpClient.Transport = transports.skipExisting
+	}
+	if configuration.Transactional && !configuration.DryRun {
+		var err error
+		if transports.existingPaths, err = newExistingPathsTransport(httpClient.Transport, uploadService.ArtDetails.GetUrl()); err != nil {
+			return nil, err
+		}
+		httpClient.Transport = transports.existingPaths
+	}
+	if changedFilter != nil {
+		transports.unchanged = &unchangedFilesTransport{transport: httpClient.Transport, filter: changedFilter, artifactoryUrl: uploadService.ArtDetails.GetUrl()}
+		httpClient.Transport = transports.unchanged
+	}
+	if configuration.MaxUploadRateKbps > 0 {
+		httpClient.Transport = &rateLimitTransport{transport: httpClient.Transport, limiter: newRateLimiter(configuration.MaxUploadRateKbps)}
+	}
+	if configuration.RetryWaitMilliSecs > 0 {
+		httpClient.Transport = newRetryWaitTransport(httpClient.Transport, configuration.RetryWaitMilliSecs)
+	}
+	if configuration.MaxTotalRetries > 0 || configuration.RetriesSizeScalingMB > 0 || retryOnStatus != nil {
+		// The refused attempts are neither delayed nor counted, since the retries limit transport wraps all of the others.
+		transports.retriesLimit = &retriesLimitTransport{transport: httpClient.Transport, attempts: newUploadAttempts(getUploadRetries(configuration)), retryOnStatus: retryOnStatus}
+		if configuration.RetriesSizeScalingMB > 0 {
+			transports.retriesLimit.scaling = &retriesScaling{baseRetries: configuration.Retries, retriesSizeScalingMB: configuration.RetriesSizeScalingMB, maxRetries: configuration.MaxRetries}
+		}
+		if configuration.MaxTotalRetries > 0 {
+			transports.retriesLimit.budget = &retriesBudget{remaining: configuration.MaxTotalRetries}
+			if multipart != nil {
+				multipart.budget = transports.retriesLimit.budget
+			}
+		}
+		httpClient.Transport = transports.retriesLimit
+	}
+	return transports, nil
+}
+
+func getTransport(httpClient *http.Client) http.RoundTripper {
+	if httpClient.Transport == nil {
+		return http.DefaultTransport
+	}
+	return httpClient.Transport
+}
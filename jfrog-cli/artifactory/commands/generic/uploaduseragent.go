@@ -0,0 +1,44 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Returns the suffix of the User-Agent of the upload requests, as set by the JFROG_CLI_USER_AGENT_SUFFIX environment variable.
+func GetUserAgentSuffix() string {
+	return strings.TrimSpace(os.Getenv("JFROG_CLI_USER_AGENT_SUFFIX"))
+}
+
+func validateUserAgentSuffix(suffix string) error {
+	for _, char := range suffix {
+		if char < ' ' || char == 0x7f {
+			return errorutils.CheckError(errors.New("The User-Agent suffix cannot contain control characters: " + strings.TrimSpace(suffix)))
+		}
+	}
+	return nil
+}
+
+// An http.RoundTripper, which appends a suffix to the User-Agent of the requests, so that the uploads can be attributed
+// in the access logs of Artifactory. The base identifier of the CLI is kept at the beginning of the User-Agent.
+type userAgentTransport struct {
+	transport http.RoundTripper
+	suffix    string
+}
+
+func (ut *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	userAgentReq := *req
+	userAgentReq.Header = make(http.Header, len(req.Header))
+	for name, values := range req.Header {
+		userAgentReq.Header[name] = values
+	}
+	userAgent := req.Header.Get("User-Agent")
+	if userAgent != "" {
+		userAgent += " "
+	}
+	userAgentReq.Header.Set("User-Agent", userAgent+ut.suffix)
+	return ut.transport.RoundTrip(&userAgentReq)
+}
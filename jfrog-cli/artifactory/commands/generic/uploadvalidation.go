@@ -0,0 +1,80 @@
+package generic
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"strconv"
+	"strings"
+)
+
+// Validates the options of the upload and the spec file entries, before any of the files is uploaded.
+// The errors of all of the entries are reported together, so that they can be fixed at once.
+func validateUploadConfiguration(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) error {
+	if configuration.MinChecksumDeploySize < 0 {
+		return errorutils.CheckError(errors.New("The minimum checksum deploy size cannot be negative: " + strconv.FormatInt(configuration.MinChecksumDeploySize, 10)))
+	}
+	if configuration.ErrorMode != "" && configuration.ErrorMode != ErrorModeContinue && configuration.ErrorMode != ErrorModeFailFast {
+		return errorutils.CheckError(errors.New("The error mode should be one of: " + ErrorModeContinue + " or " + ErrorModeFailFast))
+	}
+	if configuration.Transactional && configuration.BuildFlush {
+		return errorutils.CheckError(errors.New("The build info cannot be flushed after each spec file entry of a transactional upload, since the artifacts of the entries are deleted if a later entry fails."))
+	}
+	if configuration.ChecksumAlgorithm != "" && configuration.ChecksumAlgorithm != ChecksumAlgorithmSha256 && configuration.ChecksumAlgorithm != ChecksumAlgorithmSha1 {
+		return errorutils.CheckError(errors.New("The checksum algorithm should be one of: " + ChecksumAlgorithmSha256 + " or " + ChecksumAlgorithmSha1))
+	}
+	var specErrors []string
+	for i := 0; i < len(uploadSpec.Files); i++ {
+		err := uploadSpec.Get(i).ValidateUploadOptions()
+		if err == nil {
+			// Resolving the upload params validates the pattern according to its type.
+			_, err = getUploadParams(uploadSpec.Get(i), configuration)
+		}
+		if err != nil {
+			specErrors = append(specErrors, "File spec entry "+strconv.Itoa(i+1)+": "+err.Error())
+		}
+	}
+	if len(specErrors) > 0 {
+		return errorutils.CheckError(errors.New(strings.Join(specErrors, "\n")))
+	}
+	if configuration.DeployIf != "" {
+		if err := validateDeployCondition(configuration.DeployIf); err != nil {
+			return err
+		}
+	}
+	if err := validateUserAgentSuffix(configuration.UserAgentSuffix); err != nil {
+		return err
+	}
+	if err := validateUploadTempDir(configuration.TempDir); err != nil {
+		return err
+	}
+	if err := validateTargetChecksumTokens(uploadSpec, configuration); err != nil {
+		return err
+	}
+	if configuration.ChecksumOnlyDeploy {
+		if err := validateChecksumOnlyDeploy(uploadSpec, configuration); err != nil {
+			return err
+		}
+		// The files are deployed by checksum regardless of their size.
+		configuration.MinChecksumDeploySize = 0
+	}
+	if configuration.BuildInfoTarget != "" && configuration.BuildFlush {
+		return errorutils.CheckError(errors.New("The build info artifact cannot be uploaded when the build info is flushed after each spec file entry, since the artifacts of the entries are released once they are saved."))
+	}
+	if configuration.BuildFlush && configuration.VerifyUpload {
+		return errorutils.CheckError(errors.New("The build info cannot be flushed after each spec file entry when the uploads are verified, since the verification takes place once all of the entries are uploaded."))
+	}
+	if configuration.Symlink && configuration.FollowSymlinks {
+		return errorutils.CheckError(errors.New("Symlinks cannot be both preserved and followed"))
+	}
+	if configuration.FollowSymlinks || configuration.Symlink {
+		return validateSpecSymlinks(uploadSpec, configuration)
+	}
+	return nil
+}
+
+// Returns true if the upload stops at the first spec file entry which fails.
+// A transactional upload is rolled back on the first failure, so there is no point in uploading the rest of its entries.
+func isFailFast(configuration *UploadConfiguration) bool {
+	return configuration.ErrorMode == ErrorModeFailFast || configuration.Transactional
+}
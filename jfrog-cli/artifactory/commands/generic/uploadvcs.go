@@ -0,0 +1,35 @@
+package generic
+
+import (
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/utils/git"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"os"
+)
+
+// The properties attached to the uploaded artifacts with the git revision and branch of the working directory,
+// when configuration.AddVcsProps is set.
+const (
+	VcsRevisionProp = "vcs.revision"
+	VcsBranchProp   = "vcs.branch"
+)
+
+// Returns the VCS properties of the git repository of the working directory. The revision is read exactly as it is
+// read by build-add-git, so that the artifacts and the build info record the same revision.
+// If the working directory is not a git repository, a warning is logged and an empty string is returned.
+func getVcsProps() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Warn("Skipping the VCS properties, since the working directory could not be read:", err.Error())
+		return ""
+	}
+	gitManager := git.NewManager(wd)
+	if err = gitManager.ReadConfig(); err != nil || gitManager.GetRevision() == "" {
+		log.Warn("Skipping the VCS properties, since", wd, "is not the root of a git repository.")
+		return ""
+	}
+	props := VcsRevisionProp + "=" + gitManager.GetRevision()
+	if gitManager.GetBranch() != "" {
+		props += ";" + VcsBranchProp + "=" + gitManager.GetBranch()
+	}
+	return props
+}
@@ -0,0 +1,92 @@
+package generic
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/http"
+	"net/url"
+)
+
+type storageInfo struct {
+	Checksums struct {
+		Sha1 string `json:"sha1"`
+		Md5  string `json:"md5"`
+	} `json:"checksums"`
+}
+
+// Compares the checksums of the uploaded artifacts in Artifactory, to their local checksums.
+// Artifacts deployed by checksum are skipped, since Artifactory already confirmed their checksums.
+// Returns the artifacts with mismatching checksums, or which could not be verified.
+func verifyUploads(filesInfo []clientutils.FileInfo, artifactoryUrl string, checksumDeployed func(string) bool, uploadService *services.UploadService) (failed []clientutils.FileInfo) {
+	log.Info("Verifying the checksums of the uploaded artifacts...")
+	for _, fileInfo := range filesInfo {
+		if fileInfo.FileHashes == nil || fileInfo.Sha1 == "" {
+			continue
+		}
+		if artifactoryPath, err := url.Parse(fileInfo.ArtifactoryPath); err == nil && checksumDeployed(artifactoryPath.Path) {
+			continue
+		}
+		targetPath := getRelativeTargetPath(fileInfo.ArtifactoryPath, artifactoryUrl)
+		info, err := getStorageInfo(targetPath, uploadService)
+		if err != nil {
+			log.Error("Failed verifying", targetPath+":", err)
+			failed = append(failed, fileInfo)
+			continue
+		}
+		if info.Checksums.Sha1 != fileInfo.Sha1 || info.Checksums.Md5 != fileInfo.Md5 {
+			log.Error("Checksum mismatch for", targetPath+". Local sha1:", fileInfo.Sha1, "md5:", fileInfo.Md5+". Artifactory sha1:", info.Checksums.Sha1, "md5:", info.Checksums.Md5)
+			failed = append(failed, fileInfo)
+		}
+	}
+	return
+}
+
+// Verifies the checksums of the uploaded artifacts of the result, and counts the artifacts which could not be verified
+// as failed. Returns the errors of these artifacts.
+func verifyUploadedFiles(result *uploadFilesResult, uploaders []*specUploader, configuration *UploadConfiguration) (fileErrors []*UploadFileError) {
+	failed := verifyUploads(result.filesInfo, configuration.ArtDetails.Url, isChecksumDeployedByUploaders(uploaders), uploaders[0].uploadService)
+	result.successCount -= len(failed)
+	result.failCount += len(failed)
+	if isFailuresCollected(configuration) {
+		result.failures = append(result.failures, convertFileInfoToUploadResults(failed, result, configuration.ArtDetails.Url, true)...)
+	}
+	for _, fileInfo := range failed {
+		targetPath := getRelativeTargetPath(fileInfo.ArtifactoryPath, configuration.ArtDetails.Url)
+		fileErrors = append(fileErrors, &UploadFileError{LocalPath: fileInfo.LocalPath, TargetPath: targetPath, Err: errors.New("The checksums of the uploaded artifact could not be verified.")})
+	}
+	return
+}
+
+func getStorageInfo(targetPath string, uploadService *services.UploadService) (*storageInfo, error) {
+	info, err := getExistingStorageInfo(targetPath, uploadService)
+	if err == nil && info == nil {
+		err = errorutils.CheckError(errors.New("Artifactory response: 404 Not Found"))
+	}
+	return info, err
+}
+
+// Same as getStorageInfo, but returns nil if the target path does not exist.
+func getExistingStorageInfo(targetPath string, uploadService *services.UploadService) (*storageInfo, error) {
+	storageUrl, err := clientutils.BuildArtifactoryUrl(uploadService.ArtDetails.GetUrl(), "api/storage/"+targetPath, make(map[string]string))
+	if err != nil {
+		return nil, err
+	}
+	resp, body, _, err := uploadService.GetJfrogHttpClient().SendGet(storageUrl, true, uploadService.ArtDetails.CreateHttpClientDetails())
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, errorutils.CheckError(errors.New("Artifactory response: " + resp.Status))
+	}
+	info := new(storageInfo)
+	err = json.Unmarshal(body, info)
+	return info, errorutils.CheckError(err)
+}
@@ -0,0 +1,130 @@
+package generic
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/artifactory/spec"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// The statuses of the local files, verified against their targets in Artifactory.
+const (
+	// The target exists in Artifactory with the checksums of the local file.
+	VerifyMatch = "MATCH"
+	// The target does not exist in Artifactory.
+	VerifyMissing = "MISSING"
+	// The target exists in Artifactory with different checksums.
+	VerifyMismatch = "MISMATCH"
+)
+
+// The status of a single local file, verified against its target in Artifactory.
+type UploadVerifyResult struct {
+	LocalPath  string
+	TargetPath string
+	Status     string
+}
+
+// Verifies that the local files of the upload spec were already uploaded, without uploading them. The local files and
+// their targets are resolved as they are by the upload, and their checksums are compared to the checksums of the
+// targets in Artifactory. A table of the verified files is printed.
+// Returns the number of files which match their targets, and the number of files which are missing or mismatch.
+func VerifyUpload(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) (matchCount, failCount int, err error) {
+	results, err := verifyUploadSpec(uploadSpec, configuration)
+	if err != nil {
+		return
+	}
+	for _, result := range results {
+		if result.Status == VerifyMatch {
+			matchCount++
+		} else {
+			failCount++
+		}
+	}
+	err = writeUploadVerifyResults(reportWriter, results)
+	return
+}
+
+func verifyUploadSpec(uploadSpec *spec.SpecFiles, configuration *UploadConfiguration) ([]UploadVerifyResult, error) {
+	if configuration.TargetTime.IsZero() {
+		configuration.TargetTime = time.Now()
+	}
+	servicesConfig, err := createUploadServiceConfig(configuration.ArtDetails, configuration, 1)
+	if err != nil {
+		return nil, err
+	}
+	uploadService, err := createUploadService(servicesConfig, configuration.ArtDetails, configuration)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Verifying the local files against their targets in Artifactory...")
+	var results []UploadVerifyResult
+	for i := 0; i < len(uploadSpec.Files); i++ {
+		f := uploadSpec.Get(i)
+		explode, _ := f.IsExplode(false)
+		if f.Pattern == StdinPattern || f.Archive != "" || explode {
+			return nil, errorutils.CheckError(errors.New("File spec entry " + strconv.Itoa(i+1) + ": The content read from stdin, archived or extracted while it is uploaded cannot be verified."))
+		}
+		uploadParams, err := getUploadParams(f, configuration)
+		if err != nil {
+			return nil, err
+		}
+		files, err := collectFilesForUpload(uploadParams, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			// Symlinks are uploaded as empty files, so there is no content to verify.
+			if file.isDir || (file.symlink != "" && uploadParams.IsSymlink()) {
+				continue
+			}
+			result, err := verifyUploadFile(file, uploadService)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// Compares the checksums of the local file to the checksums of its target in Artifactory.
+func verifyUploadFile(file uploadFile, uploadService *services.UploadService) (UploadVerifyResult, error) {
+	result := UploadVerifyResult{LocalPath: file.localPath, TargetPath: file.targetPath, Status: VerifyMissing}
+	details, err := fileutils.GetFileDetails(file.localPath)
+	if err != nil {
+		return result, err
+	}
+	info, err := getExistingStorageInfo(file.targetPath, uploadService)
+	if err != nil || info == nil {
+		return result, err
+	}
+	result.Status = VerifyMatch
+	if info.Checksums.Sha1 != details.Checksum.Sha1 || info.Checksums.Md5 != details.Checksum.Md5 {
+		log.Debug("Checksum mismatch for", file.targetPath+". Local sha1:", details.Checksum.Sha1, "md5:", details.Checksum.Md5+". Artifactory sha1:", info.Checksums.Sha1, "md5:", info.Checksums.Md5)
+		result.Status = VerifyMismatch
+	}
+	return result, nil
+}
+
+// Writes an aligned table of the verified files and their statuses.
+func writeUploadVerifyResults(writer io.Writer, results []UploadVerifyResult) error {
+	counts := make(map[string]int)
+	tw := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tSOURCE\tTARGET")
+	for _, result := range results {
+		fmt.Fprintln(tw, result.Status+"\t"+result.LocalPath+"\t"+result.TargetPath)
+		counts[result.Status]++
+	}
+	if err := tw.Flush(); errorutils.CheckError(err) != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(writer, "Match:", counts[VerifyMatch], "Missing:", counts[VerifyMissing], "Mismatch:", counts[VerifyMismatch])
+	return errorutils.CheckError(err)
+}
@@ -18,6 +18,15 @@ type builder struct {
 	regexp          bool
 	includeDirs     bool
 	archiveEntries	string
+	contentType     string
+	archive         string
+	threads         int
+	emptyDirPlaceholder string
+	patternType     string
+	asDependency    bool
+	module          string
+	explodeTargetStructure bool
+	contentEncoding        string
 }
 
 func NewBuilder() *builder {
@@ -99,6 +108,51 @@ func (b *builder) IncludeDirs(includeDirs bool) *builder {
 	return b
 }
 
+func (b *builder) ContentType(contentType string) *builder {
+	b.contentType = contentType
+	return b
+}
+
+func (b *builder) ContentEncoding(contentEncoding string) *builder {
+	b.contentEncoding = contentEncoding
+	return b
+}
+
+func (b *builder) Archive(archive string) *builder {
+	b.archive = archive
+	return b
+}
+
+func (b *builder) Threads(threads int) *builder {
+	b.threads = threads
+	return b
+}
+
+func (b *builder) EmptyDirPlaceholder(emptyDirPlaceholder string) *builder {
+	b.emptyDirPlaceholder = emptyDirPlaceholder
+	return b
+}
+
+func (b *builder) PatternType(patternType string) *builder {
+	b.patternType = patternType
+	return b
+}
+
+func (b *builder) AsDependency(asDependency bool) *builder {
+	b.asDependency = asDependency
+	return b
+}
+
+func (b *builder) Module(module string) *builder {
+	b.module = module
+	return b
+}
+
+func (b *builder) ExplodeTargetStructure(explodeTargetStructure bool) *builder {
+	b.explodeTargetStructure = explodeTargetStructure
+	return b
+}
+
 func (b *builder) BuildSpec() *SpecFiles {
 	return &SpecFiles{
 		Files: []File{
@@ -118,6 +172,15 @@ func (b *builder) BuildSpec() *SpecFiles {
 				Regexp:          strconv.FormatBool(b.regexp),
 				IncludeDirs:     strconv.FormatBool(b.includeDirs),
 				ArchiveEntries:	 b.archiveEntries,
+				ContentType:     b.contentType,
+				Archive:         b.archive,
+				Threads:         strconv.Itoa(b.threads),
+				EmptyDirPlaceholder: b.emptyDirPlaceholder,
+				PatternType:     b.patternType,
+				AsDependency:    strconv.FormatBool(b.asDependency),
+				Module:          b.module,
+				ExplodeTargetStructure: strconv.FormatBool(b.explodeTargetStructure),
+				ContentEncoding:        b.contentEncoding,
 			},
 		},
 	}
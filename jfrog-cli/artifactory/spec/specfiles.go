@@ -10,11 +10,34 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
 )
 
 const fileSpecWithBuildNoRepoValidationMessage = "Spec cannot include both 'build' and '%s', if 'pattern' is empty or '*'."
 const fileSpecCannotIncludeBothPropertiesValidationMessage = "Spec cannot include both '%s' and '%s.'"
 
+// The types of the patterns, which are used to collect the files to upload.
+const (
+	// The * and ? wildcards, where * matches any number of characters, including the path separators.
+	WildcardPatternType = "wildcard"
+	// A regular expression, in the syntax of the Go regexp package.
+	RegexpPatternType = "regexp"
+	// An Ant-style pattern, where ** matches any number of directories, and * and ? match within a single path segment.
+	AntPatternType = "ant"
+)
+
+var PatternTypes = []string{WildcardPatternType, RegexpPatternType, AntPatternType}
+
+// The path of the File Spec, by which the File Spec is read from stdin.
+const StdinSpecPath = "-"
+
+// The reader of the File Spec read from stdin. Replaced by tests.
+var stdin io.Reader = os.Stdin
+
 type SpecFiles struct {
 	Files []File
 }
@@ -26,10 +49,12 @@ func (spec *SpecFiles) Get(index int) *File {
 	return new(File)
 }
 
+// Reads the File Spec in the specified path, or from stdin if the path is StdinSpecPath.
+// The variables of the File Spec are replaced by the specVars, before it is parsed.
 func CreateSpecFromFile(specFilePath string, specVars map[string]string) (spec *SpecFiles, err error) {
 	spec = new(SpecFiles)
-	content, err := fileutils.ReadFile(specFilePath)
-	if errorutils.CheckError(err) != nil {
+	content, err := readSpecContent(specFilePath)
+	if err != nil {
 		return
 	}
 
@@ -38,12 +63,43 @@ func CreateSpecFromFile(specFilePath string, specVars map[string]string) (spec *
 	}
 
 	err = json.Unmarshal(content, spec)
-	if errorutils.CheckError(err) != nil {
-		return
+	if err != nil {
+		err = errorutils.CheckError(createSpecParseError(content, err))
 	}
 	return
 }
 
+// Reads the content of the File Spec in the specified path, or from stdin if the path is StdinSpecPath.
+func readSpecContent(specFilePath string) ([]byte, error) {
+	if specFilePath != StdinSpecPath {
+		content, err := fileutils.ReadFile(specFilePath)
+		return content, errorutils.CheckError(err)
+	}
+	content, err := ioutil.ReadAll(stdin)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, errorutils.CheckError(errors.New("The File Spec read from stdin is empty."))
+	}
+	return content, nil
+}
+
+// Returns an error describing the failure to parse the File Spec, with the byte offset of the failure when known.
+func createSpecParseError(content []byte, err error) error {
+	var offset int64 = -1
+	switch parseErr := err.(type) {
+	case *json.SyntaxError:
+		offset = parseErr.Offset
+	case *json.UnmarshalTypeError:
+		offset = parseErr.Offset
+	}
+	if offset < 0 {
+		return errors.New("Failed parsing the File Spec: " + err.Error())
+	}
+	return errors.New("Failed parsing the File Spec at byte offset " + strconv.FormatInt(offset, 10) + ": " + err.Error())
+}
+
 func replaceSpecVars(content []byte, specVars map[string]string) []byte {
 	log.Debug("Replacing variables in the provided File Spec: \n" + string(content))
 	for key, val := range specVars {
@@ -55,6 +111,40 @@ func replaceSpecVars(content []byte, specVars map[string]string) []byte {
 	return content
 }
 
+// Expands the environment variables referenced in the patterns, targets and props of the spec, as ${VAR} or $VAR.
+// A literal dollar sign followed by a variable name should be escaped as $$. A dollar sign which is not followed by a
+// variable name, such as the end-of-line anchor of a regular expression, is left as is.
+// Returns an error if a referenced variable is not set, unless ignoreMissing is true, in which case the reference is left as is.
+func (spec *SpecFiles) ExpandEnvVars(ignoreMissing bool) error {
+	var missing []string
+	isMissing := make(map[string]bool)
+	expand := func(value string) string {
+		return os.Expand(value, func(name string) string {
+			if name == "$" {
+				return "$"
+			}
+			if value, ok := os.LookupEnv(name); ok {
+				return value
+			}
+			if !isMissing[name] {
+				isMissing[name] = true
+				missing = append(missing, name)
+			}
+			return "${" + name + "}"
+		})
+	}
+	for i := range spec.Files {
+		file := &spec.Files[i]
+		file.Pattern = expand(file.Pattern)
+		file.Target = expand(file.Target)
+		file.Props = expand(file.Props)
+	}
+	if len(missing) > 0 && !ignoreMissing {
+		return errorutils.CheckError(errors.New("The File Spec references environment variables, which are not set: " + strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
 type File struct {
 	Aql             utils.Aql
 	Pattern         string
@@ -72,6 +162,24 @@ type File struct {
 	Regexp          string
 	IncludeDirs     string
 	ArchiveEntries  string
+	ContentType     string
+	Archive         string
+	// The number of threads uploading the files of this spec file. Overrides the global number of threads if set.
+	Threads string
+	// The name of an empty placeholder file, which is uploaded into each of the matched empty directories when includeDirs is set.
+	EmptyDirPlaceholder string
+	// The type of the pattern, one of the pattern types. If not set, the type is determined by the regexp option.
+	PatternType string
+	// If true, the uploaded files are also added to the build info as dependencies of the build.
+	AsDependency string
+	// The id of the build info module of the uploaded files. If not set, the build name is used.
+	Module string
+	// If true, the entries of the exploded archives are uploaded under the target, preserving the directory structure
+	// of the archives. Used only together with the explode option.
+	ExplodeTargetStructure string
+	// The Content-Encoding header of the uploaded files, such as gzip. The files are not compressed by the upload,
+	// so they are expected to be already encoded.
+	ContentEncoding string
 }
 
 func (f File) IsFlat(defaultValue bool) (bool, error) {
@@ -86,6 +194,26 @@ func (f File) IsRegexp(defaultValue bool) (bool, error) {
 	return clientutils.StringToBool(f.Regexp, defaultValue)
 }
 
+// Returns the type of the pattern. If the patternType option is not set, the type is regexp or wildcard,
+// according to the regexp option.
+func (f File) GetPatternType() (string, error) {
+	switch f.PatternType {
+	case WildcardPatternType, RegexpPatternType, AntPatternType:
+		return f.PatternType, nil
+	case "":
+		isRegexp, err := f.IsRegexp(false)
+		if err != nil {
+			return "", err
+		}
+		if isRegexp {
+			return RegexpPatternType, nil
+		}
+		return WildcardPatternType, nil
+	default:
+		return "", errorutils.CheckError(errors.New("The 'patternType' option should be one of " + strings.Join(PatternTypes, ", ") + ", but got: " + f.PatternType))
+	}
+}
+
 func (f File) IsRecursive(defaultValue bool) (bool, error) {
 	return clientutils.StringToBool(f.Recursive, defaultValue)
 }
@@ -94,6 +222,77 @@ func (f File) IsIncludeDirs(defaultValue bool) (bool, error) {
 	return clientutils.StringToBool(f.IncludeDirs, defaultValue)
 }
 
+func (f File) IsAsDependency(defaultValue bool) (bool, error) {
+	return clientutils.StringToBool(f.AsDependency, defaultValue)
+}
+
+func (f File) IsExplodeTargetStructure(defaultValue bool) (bool, error) {
+	return clientutils.StringToBool(f.ExplodeTargetStructure, defaultValue)
+}
+
+// Returns the number of threads uploading the files of this spec file, or the default value if the threads option is
+// not set. 0 means that the global number of threads is used.
+func (f File) GetThreads(defaultValue int) (int, error) {
+	if f.Threads == "" {
+		return defaultValue, nil
+	}
+	threads, err := strconv.Atoi(f.Threads)
+	if err != nil || threads < 0 {
+		return 0, errorutils.CheckError(errors.New("The value of 'threads' should be a non-negative number, but got: " + f.Threads))
+	}
+	return threads, nil
+}
+
+// Returns an error if the file group combines upload options, which cannot be used together, or if any of its boolean
+// upload options is invalid. Only the options which are explicitly set are considered, since the defaults never conflict.
+func (f File) ValidateUploadOptions() error {
+	isFlat, err := f.IsFlat(false)
+	if err != nil {
+		return err
+	}
+	isExplode, err := f.IsExplode(false)
+	if err != nil {
+		return err
+	}
+	isRegexp, err := f.IsRegexp(false)
+	if err != nil {
+		return err
+	}
+	isExplodeTargetStructure, err := f.IsExplodeTargetStructure(false)
+	if err != nil {
+		return err
+	}
+	isIncludeDirs, err := f.IsIncludeDirs(false)
+	if err != nil {
+		return err
+	}
+	if isFlat && isExplode {
+		return errors.New("The 'flat' and 'explode' options cannot be used together, since the archive is extracted into its target path regardless of 'flat'.")
+	}
+	patternType, err := f.GetPatternType()
+	if err != nil {
+		return err
+	}
+	if isRegexp && patternType != RegexpPatternType {
+		return errors.New("The 'regexp' option cannot be used with the '" + patternType + "' pattern type.")
+	}
+	if patternType == RegexpPatternType && strings.Contains(f.Pattern, "**") {
+		return errors.New("The 'regexp' option cannot be used with a 'pattern' containing the Ant-style '**' wildcard.")
+	}
+	if isExplodeTargetStructure && !isExplode {
+		return errors.New("The 'explodeTargetStructure' option can be used only together with the 'explode' option.")
+	}
+	if f.EmptyDirPlaceholder != "" {
+		if !isIncludeDirs {
+			return errors.New("The 'emptyDirPlaceholder' option can be used only together with the 'includeDirs' option.")
+		}
+		if strings.ContainsAny(f.EmptyDirPlaceholder, "/\\") {
+			return errors.New("The 'emptyDirPlaceholder' option should be a file name, rather than a path: " + f.EmptyDirPlaceholder)
+		}
+	}
+	return nil
+}
+
 func (f *File) ToArtifactoryCommonParams() *utils.ArtifactoryCommonParams {
 	params := new(utils.ArtifactoryCommonParams)
 	params.Aql = f.Aql
@@ -145,6 +344,9 @@ func ValidateSpec(files []File, isTargetMandatory, isSearchBasedSpec bool) error
 		if isSortOrder && !isValidSortOrder {
 			return errors.New("The value of 'sort-order'can only be 'asc' or 'desc'.")
 		}
+		if _, err := file.GetThreads(0); err != nil {
+			return err
+		}
 		if isBuild && isSearchBasedSpec {
 			err := validateFileSpecWithBuild(file, isExcludePattern)
 			if err != nil {
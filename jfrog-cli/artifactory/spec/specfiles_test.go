@@ -2,6 +2,9 @@ package spec
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -40,3 +43,77 @@ func assertVariablesMap(expected, actual []byte, t *testing.T) {
 		t.Error("Wrong matching expected: `" + string(expected) + "` Got `" + string(actual) + "`")
 	}
 }
+
+func TestExpandEnvVars(t *testing.T) {
+	os.Setenv("SPEC_TEST_REPO", "libs-release")
+	os.Setenv("SPEC_TEST_VERSION", "1.2.3")
+	os.Unsetenv("SPEC_TEST_MISSING")
+	defer os.Unsetenv("SPEC_TEST_REPO")
+	defer os.Unsetenv("SPEC_TEST_VERSION")
+
+	spec := NewBuilder().Pattern("build/(.*)-$SPEC_TEST_VERSION\\.jar$").Target("${SPEC_TEST_REPO}/{1}/").Props("version=${SPEC_TEST_VERSION};price=$$5").BuildSpec()
+	if err := spec.ExpandEnvVars(false); err != nil {
+		t.Fatal(err)
+	}
+	file := spec.Get(0)
+	if file.Pattern != "build/(.*)-1.2.3\\.jar$" || file.Target != "libs-release/{1}/" || file.Props != "version=1.2.3;price=$5" {
+		t.Error("Unexpected expansion:", file.Pattern, file.Target, file.Props)
+	}
+
+	spec = NewBuilder().Pattern("build/*").Target("repo/${SPEC_TEST_MISSING}/$SPEC_TEST_MISSING/").BuildSpec()
+	err := spec.ExpandEnvVars(false)
+	if err == nil || !strings.HasSuffix(err.Error(), ": SPEC_TEST_MISSING") {
+		t.Error("Expected an error for the missing variable, got:", err)
+	}
+	spec = NewBuilder().Pattern("build/*").Target("repo/${SPEC_TEST_MISSING}/").BuildSpec()
+	if err = spec.ExpandEnvVars(true); err != nil || spec.Get(0).Target != "repo/${SPEC_TEST_MISSING}/" {
+		t.Error("Expected the missing variable to be left as is, got:", spec.Get(0).Target, err)
+	}
+}
+
+func TestSpecVarsPrecedeEnvVars(t *testing.T) {
+	os.Setenv("SPEC_TEST_VERSION", "1.0.0")
+	os.Setenv("SPEC_TEST_CHANNEL", "stable")
+	defer os.Unsetenv("SPEC_TEST_VERSION")
+	defer os.Unsetenv("SPEC_TEST_CHANNEL")
+	specFile, err := ioutil.TempFile("", "spec_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(specFile.Name())
+	specFile.WriteString(`{"files": [{"pattern": "build/*-${SPEC_TEST_VERSION}.jar", "target": "repo/${SPEC_TEST_CHANNEL}/"}]}`)
+	specFile.Close()
+
+	spec, err := CreateSpecFromFile(specFile.Name(), map[string]string{"SPEC_TEST_VERSION": "1.2.3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = spec.ExpandEnvVars(false); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Get(0).Pattern != "build/*-1.2.3.jar" || spec.Get(0).Target != "repo/stable/" {
+		t.Error("Expected the spec vars to take precedence over the environment variables, got:", spec.Get(0).Pattern, spec.Get(0).Target)
+	}
+}
+
+func TestCreateSpecFromStdin(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader(`{"files": [{"pattern": "${dir}/*.txt", "target": "repo/"}]}`)
+	spec, err := CreateSpecFromFile(StdinSpecPath, map[string]string{"dir": "out"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Files) != 1 || spec.Get(0).Pattern != "out/*.txt" || spec.Get(0).Target != "repo/" {
+		t.Error("Unexpected File Spec read from stdin:", spec.Files)
+	}
+
+	stdin = strings.NewReader(" \n")
+	if _, err = CreateSpecFromFile(StdinSpecPath, nil); err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Error("Expected an error for an empty File Spec, got:", err)
+	}
+
+	stdin = strings.NewReader(`{"files": [{"pattern": "a" "target": "repo/"}]}`)
+	if _, err = CreateSpecFromFile(StdinSpecPath, nil); err == nil || !strings.Contains(err.Error(), "byte offset 28") {
+		t.Error("Expected an error with the byte offset of the malformed JSON, got:", err)
+	}
+}
@@ -0,0 +1,251 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The fields of File, which hold boolean values as strings.
+var booleanFields = []string{"Recursive", "Flat", "Regexp", "IncludeDirs", "Explode", "AsDependency", "ExplodeTargetStructure"}
+
+// An error found while validating a File Spec.
+type ValidationError struct {
+	// The line of the File Spec, in which the error was found. 0 if unknown.
+	Line int
+	// The content of the line, in which the error was found.
+	Context string
+	// The path of the invalid field, for example files[0].flat.
+	Field   string
+	Message string
+}
+
+func (validationError ValidationError) String() string {
+	location := "line " + strconv.Itoa(validationError.Line)
+	if validationError.Field != "" {
+		location += ", " + validationError.Field
+	}
+	message := location + ": " + validationError.Message
+	if validationError.Context != "" {
+		message += "\n\t" + validationError.Context
+	}
+	return message
+}
+
+// Validates the File Spec in the specified path, or read from stdin if the path is StdinSpecPath, and returns the
+// errors found in it. The types of the fields are checked before the File Spec is parsed the same way
+// CreateSpecFromFile parses it, so that each invalid field is reported with its location, rather than failing on the
+// first invalid field. Returns an error only if the File Spec could not be read.
+func ValidateSpecFile(specFilePath string, isTargetMandatory, isSearchBasedSpec bool) ([]ValidationError, error) {
+	content, err := readSpecContent(specFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return validateSpecContent(content, isTargetMandatory, isSearchBasedSpec), nil
+}
+
+func validateSpecContent(content []byte, isTargetMandatory, isSearchBasedSpec bool) []ValidationError {
+	validator := &specValidator{content: content, decoder: json.NewDecoder(bytes.NewReader(content))}
+	fileLines, ok := validator.validateFields()
+	if !ok || len(validator.errors) > 0 {
+		return validator.errors
+	}
+	spec := new(SpecFiles)
+	if err := json.Unmarshal(content, spec); err != nil {
+		return []ValidationError{{Message: err.Error()}}
+	}
+	if len(spec.Files) == 0 {
+		return []ValidationError{validator.createError(0, "files", "Spec must include at least one file group")}
+	}
+	for i, file := range spec.Files {
+		err := ValidateSpec([]File{file}, isTargetMandatory, isSearchBasedSpec)
+		if err == nil && !isSearchBasedSpec {
+			err = file.ValidateUploadOptions()
+		}
+		if err != nil {
+			validator.errors = append(validator.errors, validator.createError(fileLines[i], "files["+strconv.Itoa(i)+"]", err.Error()))
+		}
+	}
+	return validator.errors
+}
+
+type specValidator struct {
+	content []byte
+	decoder *json.Decoder
+	errors  []ValidationError
+}
+
+// Validates the keys and the types of the values of the File Spec, while walking through its JSON tokens.
+// Returns the line of each file group, and false if the JSON is malformed.
+func (validator *specValidator) validateFields() (fileLines []int, ok bool) {
+	if !validator.expectDelim('{', "") {
+		return nil, false
+	}
+	for validator.decoder.More() {
+		key, line, ok := validator.readKey()
+		if !ok {
+			return nil, false
+		}
+		if !strings.EqualFold(key, "files") {
+			validator.errors = append(validator.errors, validator.createError(line, key, "Unknown field '"+key+"'."))
+			if _, ok = validator.readValue(); !ok {
+				return nil, false
+			}
+			continue
+		}
+		if !validator.expectDelim('[', "files") {
+			return nil, false
+		}
+		for i := 0; validator.decoder.More(); i++ {
+			filePath := "files[" + strconv.Itoa(i) + "]"
+			if !validator.expectDelim('{', filePath) {
+				return nil, false
+			}
+			fileLines = append(fileLines, validator.getLine(validator.decoder.InputOffset()))
+			if !validator.validateFileFields(filePath) || !validator.expectDelim('}', filePath) {
+				return nil, false
+			}
+		}
+		if !validator.expectDelim(']', "files") {
+			return nil, false
+		}
+	}
+	return fileLines, validator.expectDelim('}', "")
+}
+
+func (validator *specValidator) validateFileFields(filePath string) bool {
+	fields := make(map[string]reflect.StructField)
+	fileType := reflect.TypeOf(File{})
+	for i := 0; i < fileType.NumField(); i++ {
+		fields[strings.ToLower(fileType.Field(i).Name)] = fileType.Field(i)
+	}
+	for validator.decoder.More() {
+		key, line, ok := validator.readKey()
+		if !ok {
+			return false
+		}
+		value, ok := validator.readValue()
+		if !ok {
+			return false
+		}
+		fieldPath := filePath + "." + key
+		field, ok := fields[strings.ToLower(key)]
+		if !ok {
+			validator.errors = append(validator.errors, validator.createError(line, fieldPath, "Unknown field '"+key+"'."))
+			continue
+		}
+		if message := validateFieldValue(field, value); message != "" {
+			validator.errors = append(validator.errors, validator.createError(line, fieldPath, message))
+		}
+	}
+	return true
+}
+
+// Returns a message describing why the value is invalid for the field, or an empty string if it is valid.
+func validateFieldValue(field reflect.StructField, value json.RawMessage) string {
+	if err := json.Unmarshal(value, reflect.New(field.Type).Interface()); err != nil {
+		if isBooleanField(field.Name) {
+			return "Expected a boolean value as a string, \"true\" or \"false\", but got " + string(value) + "."
+		}
+		return "Expected " + describeType(field.Type) + ", but got " + string(value) + "."
+	}
+	if isBooleanField(field.Name) {
+		var stringValue string
+		json.Unmarshal(value, &stringValue)
+		if _, err := clientutils.StringToBool(stringValue, false); err != nil {
+			return "Expected \"true\" or \"false\", but got " + string(value) + "."
+		}
+	}
+	return ""
+}
+
+func describeType(fieldType reflect.Type) string {
+	switch fieldType.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Int:
+		return "a number"
+	case reflect.Slice:
+		return "an array of " + strings.TrimPrefix(describeType(fieldType.Elem()), "a ") + "s"
+	default:
+		return "an object"
+	}
+}
+
+func isBooleanField(name string) bool {
+	for _, booleanField := range booleanFields {
+		if name == booleanField {
+			return true
+		}
+	}
+	return false
+}
+
+// Reads the next key of an object, and returns the line in which it was found.
+func (validator *specValidator) readKey() (key string, line int, ok bool) {
+	token, ok := validator.readToken("")
+	if !ok {
+		return "", 0, false
+	}
+	key, _ = token.(string)
+	return key, validator.getLine(validator.decoder.InputOffset()), true
+}
+
+func (validator *specValidator) readValue() (json.RawMessage, bool) {
+	var value json.RawMessage
+	if err := validator.decoder.Decode(&value); err != nil {
+		validator.addSyntaxError(err, "")
+		return nil, false
+	}
+	return value, true
+}
+
+func (validator *specValidator) expectDelim(delim json.Delim, fieldPath string) bool {
+	token, ok := validator.readToken(fieldPath)
+	if !ok {
+		return false
+	}
+	if token != delim {
+		validator.errors = append(validator.errors, validator.createError(validator.getLine(validator.decoder.InputOffset()), fieldPath, fmt.Sprintf("Expected '%v', but got %v.", delim, token)))
+		return false
+	}
+	return true
+}
+
+func (validator *specValidator) readToken(fieldPath string) (json.Token, bool) {
+	token, err := validator.decoder.Token()
+	if err != nil {
+		validator.addSyntaxError(err, fieldPath)
+		return nil, false
+	}
+	return token, true
+}
+
+func (validator *specValidator) addSyntaxError(err error, fieldPath string) {
+	offset := validator.decoder.InputOffset()
+	if syntaxError, ok := err.(*json.SyntaxError); ok {
+		offset = syntaxError.Offset
+	}
+	validator.errors = append(validator.errors, validator.createError(validator.getLine(offset), fieldPath, "Invalid JSON: "+err.Error()))
+}
+
+func (validator *specValidator) createError(line int, fieldPath, message string) ValidationError {
+	validationError := ValidationError{Line: line, Field: fieldPath, Message: message}
+	if line > 0 {
+		validationError.Context = strings.TrimSpace(strings.Split(string(validator.content), "\n")[line-1])
+	}
+	return validationError
+}
+
+// Returns the line of the content at the offset. The offsets returned by the decoder point right after the last read token,
+// so they are on the line of that token.
+func (validator *specValidator) getLine(offset int64) int {
+	if offset > int64(len(validator.content)) {
+		offset = int64(len(validator.content))
+	}
+	return bytes.Count(validator.content[:offset], []byte("\n")) + 1
+}
@@ -0,0 +1,79 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSpecContent(t *testing.T) {
+	content := `{
+  "files": [
+    {
+      "pattern": "build/*.jar",
+      "target": "repo/",
+      "flat": true,
+      "recursive": "maybe",
+      "limit": "10",
+      "colour": "blue"
+    },
+    {
+      "pattern": "build/*.zip",
+      "target": "repo/",
+      "flat": "true",
+      "explode": "true"
+    }
+  ]
+}`
+	validationErrors := validateSpecContent([]byte(content), true, false)
+	expected := []ValidationError{
+		{Line: 6, Field: "files[0].flat", Context: `"flat": true,`},
+		{Line: 7, Field: "files[0].recursive", Context: `"recursive": "maybe",`},
+		{Line: 8, Field: "files[0].limit", Context: `"limit": "10",`},
+		{Line: 9, Field: "files[0].colour", Context: `"colour": "blue"`},
+	}
+	if len(validationErrors) != len(expected) {
+		t.Fatal("Expected", len(expected), "errors, got:", validationErrors)
+	}
+	for i, validationError := range validationErrors {
+		if validationError.Line != expected[i].Line || validationError.Field != expected[i].Field || validationError.Context != expected[i].Context {
+			t.Errorf("Expected an error at line %d in %s, got: %s", expected[i].Line, expected[i].Field, validationError.String())
+		}
+	}
+
+	content = `{"files": [{"pattern": "build/*.zip", "target": "repo/", "explodeTargetStructure": "maybe"}]}`
+	validationErrors = validateSpecContent([]byte(content), true, false)
+	if len(validationErrors) != 1 || validationErrors[0].Field != "files[0].explodeTargetStructure" {
+		t.Error("Expected an invalid boolean error in explodeTargetStructure, got:", validationErrors)
+	}
+
+	// The combinations of the options are checked once the types are valid.
+	content = `{"files": [{"pattern": "build/*.jar", "target": "repo/"},
+  {"pattern": "build/*.zip", "target": "repo/", "flat": "true", "explode": "true"}]}`
+	validationErrors = validateSpecContent([]byte(content), true, false)
+	if len(validationErrors) != 1 || validationErrors[0].Line != 2 || validationErrors[0].Field != "files[1]" || !strings.Contains(validationErrors[0].Message, "'flat' and 'explode'") {
+		t.Error("Expected a conflicting options error in the second file group, got:", validationErrors)
+	}
+
+	content = `{"files": [{"pattern": "build/*.jar", "target": "repo/", "threads": "-1"}]}`
+	validationErrors = validateSpecContent([]byte(content), true, false)
+	if len(validationErrors) != 1 || validationErrors[0].Field != "files[0]" || !strings.Contains(validationErrors[0].Message, "'threads'") {
+		t.Error("Expected an invalid threads error in the file group, got:", validationErrors)
+	}
+
+	// The rules of ValidateSpec are checked once the types are valid.
+	content = `{"files": [{"pattern": "build/*.jar", "target": "repo/"},
+  {"pattern": "build/*.zip"}]}`
+	validationErrors = validateSpecContent([]byte(content), true, false)
+	if len(validationErrors) != 1 || validationErrors[0].Line != 2 || validationErrors[0].Field != "files[1]" || !strings.Contains(validationErrors[0].Message, "target") {
+		t.Error("Expected a missing target error in the second file group, got:", validationErrors)
+	}
+	if validationErrors = validateSpecContent([]byte(content), false, true); len(validationErrors) != 0 {
+		t.Error("Expected a valid search based File Spec, got:", validationErrors)
+	}
+
+	// Malformed JSON is reported with its line.
+	validationErrors = validateSpecContent([]byte("{\"files\": [\n{\"pattern\": \"a\",,}]}"), true, false)
+	if len(validationErrors) != 1 || validationErrors[0].Line != 2 || !strings.HasPrefix(validationErrors[0].Message, "Invalid JSON") {
+		t.Error("Expected an invalid JSON error at line 2, got:", validationErrors)
+	}
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// The lock of the partials directory of a build is a directory, since creating a directory is atomic, and the
+// directories in the partials directory are not read as partial build infos.
+const partialsLockDirName = ".lock"
+
+// The lock is considered abandoned by a process which was killed while holding it, once it is older than this.
+const partialsLockStaleAge = time.Minute
+
+// The time to wait for the lock, before giving up.
+const partialsLockTimeout = 2 * time.Minute
+
+const partialsLockRetryInterval = 50 * time.Millisecond
+
+// Locks the partials directory of the build, so that the partial build infos of the build are changed by a single
+// process at a time. The returned function releases the lock.
+func lockPartialsBuildDir(partialsBuildDir string) (unlock func(), err error) {
+	lockPath := filepath.Join(partialsBuildDir, partialsLockDirName)
+	deadline := time.Now().Add(partialsLockTimeout)
+	for {
+		err = os.Mkdir(lockPath, 0700)
+		if err == nil {
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, errorutils.CheckError(err)
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > partialsLockStaleAge {
+			log.Warn("Removing the abandoned lock of the build info at:", lockPath)
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errorutils.CheckError(errors.New("Timed out waiting for the lock of the build info at: " + lockPath))
+		}
+		time.Sleep(partialsLockRetryInterval)
+	}
+}
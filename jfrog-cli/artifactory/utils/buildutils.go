@@ -14,6 +14,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -38,10 +39,18 @@ func CreateBuildProperties(buildName, buildNumber string) (string, error) {
 	}
 	buildGeneralDetails, err := ReadBuildInfoGeneralDetails(buildName, buildNumber)
 	if err != nil {
-		return fmt.Sprintf("build.name=%s;build.number=%s", buildName, buildNumber), err
+		return CreateBuildPropertiesWithoutTimestamp(buildName, buildNumber), err
 	}
 	timestamp := strconv.FormatInt(buildGeneralDetails.Timestamp.UnixNano()/int64(time.Millisecond), 10)
-	return fmt.Sprintf("build.name=%s;build.number=%s;build.timestamp=%s", buildName, buildNumber, timestamp), nil
+	return CreateBuildPropertiesWithoutTimestamp(buildName, buildNumber) + ";build.timestamp=" + timestamp, nil
+}
+
+// Returns the build.name and build.number properties, without the build.timestamp property.
+func CreateBuildPropertiesWithoutTimestamp(buildName, buildNumber string) string {
+	if buildName == "" || buildNumber == "" {
+		return ""
+	}
+	return fmt.Sprintf("build.name=%s;build.number=%s", buildName, buildNumber)
 }
 
 func getPartialsBuildDir(buildName, buildNumber string) (string, error) {
@@ -71,6 +80,11 @@ func saveBuildData(action interface{}, buildName, buildNumber string) error {
 	if err != nil {
 		return err
 	}
+	unlock, err := lockPartialsBuildDir(dirPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	log.Debug("Creating temp build file at:", dirPath)
 	tempFile, err := ioutil.TempFile(dirPath, "temp")
 	if err != nil {
@@ -107,13 +121,23 @@ func SaveBuildInfo(buildName, buildNumber string, buildInfo *buildinfo.BuildInfo
 
 func SaveBuildGeneralDetails(buildName, buildNumber string) error {
 	partialsBuildDir, err := getPartialsBuildDir(buildName, buildNumber)
-	log.Debug("Saving build general details at: " + partialsBuildDir)
 	if err != nil {
 		return err
 	}
+	unlock, err := lockPartialsBuildDir(partialsBuildDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return saveBuildGeneralDetails(partialsBuildDir)
+}
+
+// Saves the general details of the build, unless they were already saved. Must be called while holding the lock of the
+// partials directory, so that the build is started once by the processes collecting it.
+func saveBuildGeneralDetails(partialsBuildDir string) error {
+	log.Debug("Saving build general details at: " + partialsBuildDir)
 	detailsFilePath := filepath.Join(partialsBuildDir, BuildInfoDetails)
-	var exists bool
-	exists, err = fileutils.IsFileExists(detailsFilePath, false)
+	exists, err := fileutils.IsFileExists(detailsFilePath, false)
 	if err != nil {
 		return err
 	}
@@ -136,6 +160,72 @@ func SaveBuildGeneralDetails(buildName, buildNumber string) error {
 	return errorutils.CheckError(err)
 }
 
+// The general details of the build, together with the key of the Artifactory project, which the build is associated with.
+type buildProjectDetails struct {
+	buildinfo.General
+	Project string `json:"Project,omitempty"`
+}
+
+// Associates the build with the Artifactory project, so that the build info is published to the project.
+// Returns an error if the build is already associated with a different project.
+func SaveBuildProject(buildName, buildNumber, project string) error {
+	partialsBuildDir, err := getPartialsBuildDir(buildName, buildNumber)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockPartialsBuildDir(partialsBuildDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err = saveBuildGeneralDetails(partialsBuildDir); err != nil {
+		return err
+	}
+	details, detailsFilePath, err := readBuildProjectDetails(buildName, buildNumber)
+	if err != nil {
+		return err
+	}
+	if details.Project == project {
+		return nil
+	}
+	if details.Project != "" {
+		return errorutils.CheckError(fmt.Errorf("The build %s/%s is already associated with the %s project.", buildName, buildNumber, details.Project))
+	}
+	details.Project = project
+	b, err := json.MarshalIndent(details, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(ioutil.WriteFile(detailsFilePath, b, 0600))
+}
+
+// Returns the key of the Artifactory project, which the build is associated with, or an empty string if there is none.
+func ReadBuildProject(buildName, buildNumber string) (string, error) {
+	details, _, err := readBuildProjectDetails(buildName, buildNumber)
+	if err != nil {
+		return "", err
+	}
+	return details.Project, nil
+}
+
+func readBuildProjectDetails(buildName, buildNumber string) (*buildProjectDetails, string, error) {
+	partialsBuildDir, err := getPartialsBuildDir(buildName, buildNumber)
+	if err != nil {
+		return nil, "", err
+	}
+	detailsFilePath := filepath.Join(partialsBuildDir, BuildInfoDetails)
+	details := new(buildProjectDetails)
+	exists, err := fileutils.IsFileExists(detailsFilePath, false)
+	if err != nil || !exists {
+		return details, detailsFilePath, err
+	}
+	content, err := fileutils.ReadFile(detailsFilePath)
+	if err != nil {
+		return nil, "", err
+	}
+	return details, detailsFilePath, errorutils.CheckError(json.Unmarshal(content, details))
+}
+
 type populatePartialBuildInfo func(partial *buildinfo.Partial)
 
 func SavePartialBuildInfo(buildName, buildNumber string, populatePartialBuildInfoFunc populatePartialBuildInfo) error {
@@ -145,6 +235,69 @@ func SavePartialBuildInfo(buildName, buildNumber string, populatePartialBuildInf
 	return saveBuildData(partialBuildInfo, buildName, buildNumber)
 }
 
+// Same as SavePartialBuildInfo, but if a partial build info matching isAppendable was already saved, such as by a previous
+// command collecting the same build, the populate function is called with it instead of a new partial build info, and it
+// is saved in place. This allows multiple processes, such as parallel stages of the build, to accumulate their data into
+// the same partial build info. The partials directory of the build is locked while the partial build info is merged.
+func AppendPartialBuildInfo(buildName, buildNumber string, isAppendable func(partial *buildinfo.Partial) bool, populatePartialBuildInfoFunc populatePartialBuildInfo) error {
+	partialsBuildDir, err := getPartialsBuildDir(buildName, buildNumber)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockPartialsBuildDir(partialsBuildDir)
+	if err != nil {
+		return err
+	}
+	partialFilePath, partialBuildInfo, err := findPartialBuildInfo(partialsBuildDir, isAppendable)
+	if err != nil || partialBuildInfo == nil {
+		unlock()
+		if err != nil {
+			return err
+		}
+		return SavePartialBuildInfo(buildName, buildNumber, populatePartialBuildInfoFunc)
+	}
+	defer unlock()
+	log.Debug("Appending to the partial build info at:", partialFilePath)
+	partialBuildInfo.Timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	populatePartialBuildInfoFunc(partialBuildInfo)
+	content, err := json.MarshalIndent(partialBuildInfo, "", "  ")
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	return errorutils.CheckError(ioutil.WriteFile(partialFilePath, content, 0600))
+}
+
+// Returns the first partial build info in the partials directory, which matches isAppendable, and the path of its file.
+// Returns a nil partial build info if none matches.
+func findPartialBuildInfo(partialsBuildDir string, isAppendable func(partial *buildinfo.Partial) bool) (string, *buildinfo.Partial, error) {
+	buildFiles, err := fileutils.ListFiles(partialsBuildDir, false)
+	if err != nil {
+		return "", nil, err
+	}
+	sort.Strings(buildFiles)
+	for _, buildFile := range buildFiles {
+		if strings.HasSuffix(buildFile, BuildInfoDetails) {
+			continue
+		}
+		dir, err := fileutils.IsDirExists(buildFile, false)
+		if err != nil {
+			return "", nil, err
+		}
+		if dir {
+			continue
+		}
+		content, err := fileutils.ReadFile(buildFile)
+		if err != nil {
+			return "", nil, err
+		}
+		partial := new(buildinfo.Partial)
+		if json.Unmarshal(content, partial) == nil && isAppendable(partial) {
+			return buildFile, partial, nil
+		}
+	}
+	return "", nil, nil
+}
+
 func GetGeneratedBuildsInfo(buildName, buildNumber string) ([]*buildinfo.BuildInfo, error) {
 	buildDir, err := GetBuildDir(buildName, buildNumber)
 	if err != nil {
@@ -181,6 +334,11 @@ func ReadPartialBuildInfoFiles(buildName, buildNumber string) (buildinfo.Partial
 	if err != nil {
 		return nil, err
 	}
+	unlock, err := lockPartialsBuildDir(partialsBuildDir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	buildFiles, err := fileutils.ListFiles(partialsBuildDir, false)
 	if err != nil {
 		return nil, err
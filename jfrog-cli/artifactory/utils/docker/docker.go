@@ -247,27 +247,7 @@ func (pullCmd *pullCmd) GetErrWriter() io.WriteCloser {
 }
 
 func CreateServiceManager(artDetails *config.ArtifactoryDetails, threads int) (*artifactory.ArtifactoryServicesManager, error) {
-	certPath, err := utils.GetJfrogSecurityDir()
-	if err != nil {
-		return nil, err
-	}
-	artAuth, err := artDetails.CreateArtAuthConfig()
-	if err != nil {
-		return nil, err
-	}
-
-	configBuilder := artifactory.NewConfigBuilder().
-		SetArtDetails(artAuth).
-		SetCertificatesPath(certPath).
-		SetLogger(log.Logger).
-		SetThreads(threads)
-
-	if threads != 0 {
-		configBuilder.SetThreads(threads)
-	}
-
-	serviceConfig, err := configBuilder.Build()
-	return artifactory.New(serviceConfig)
+	return utils.NewServicesManager(artDetails, utils.WithThreads(threads))
 }
 
 // First will try to login assuming a proxy-less tag (e.g. "registry-address/docker-repo/image:ver").
@@ -3,31 +3,14 @@ package utils
 import (
 	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/config"
 	"github.com/jfrog/jfrog-client-go/artifactory"
-	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
 func CreateDownloadServiceManager(artDetails *config.ArtifactoryDetails, flags *DownloadConfiguration) (*artifactory.ArtifactoryServicesManager, error) {
-	certPath, err := GetJfrogSecurityDir()
-	if err != nil {
-		return nil, err
-	}
-	artAuth, err := artDetails.CreateArtAuthConfig()
-	if err != nil {
-		return nil, err
-	}
-	serviceConfig, err := artifactory.NewConfigBuilder().
-		SetArtDetails(artAuth).
-		SetDryRun(flags.DryRun).
-		SetCertificatesPath(certPath).
-		SetSplitCount(flags.SplitCount).
-		SetMinSplitSize(flags.MinSplitSize).
-		SetThreads(flags.Threads).
-		SetLogger(log.Logger).
-		Build()
-	if err != nil {
-		return nil, err
-	}
-	return artifactory.New(serviceConfig)
+	return NewServicesManager(artDetails,
+		WithDryRun(flags.DryRun),
+		WithSplitCount(flags.SplitCount),
+		WithMinSplitSize(flags.MinSplitSize),
+		WithThreads(flags.Threads))
 }
 
 type DownloadConfiguration struct {
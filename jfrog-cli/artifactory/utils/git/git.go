@@ -13,6 +13,7 @@ type manager struct {
 	path     string
 	err      error
 	revision string
+	branch   string
 	url      string
 }
 
@@ -38,6 +39,11 @@ func (m *manager) GetRevision() string {
 	return m.revision
 }
 
+// Returns the checked out branch, or an empty string if the HEAD is detached.
+func (m *manager) GetBranch() string {
+	return m.branch
+}
+
 func (m *manager) readUrl() {
 	if m.err != nil {
 		return
@@ -116,6 +122,7 @@ func (m *manager) readRevision() {
 	}
 
 	// Since the revision was not returned, then we'll fetch it, by using the ref:
+	m.branch = strings.TrimPrefix(ref, "refs/heads/")
 	dotGitPath := filepath.Join(m.path, ref)
 	file, err := os.Open(dotGitPath)
 	if err != nil {
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"github.com/jfrog/jfrog-cli-go/jfrog-cli/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The settings of the services, which differ between the commands. Unset settings keep the defaults of the client.
+type servicesOptions struct {
+	threads           int
+	dryRun            bool
+	minChecksumDeploy *int64
+	splitCount        *int
+	minSplitSize      *int64
+}
+
+// Configures the services created by NewServicesConfig and NewServicesManager.
+type Option func(options *servicesOptions)
+
+// The number of threads of the services. Ignored if not positive.
+func WithThreads(threads int) Option {
+	return func(options *servicesOptions) {
+		options.threads = threads
+	}
+}
+
+func WithDryRun(dryRun bool) Option {
+	return func(options *servicesOptions) {
+		options.dryRun = dryRun
+	}
+}
+
+// The minimum size in bytes of the files, which are deployed by checksum.
+func WithMinChecksumDeploySize(minChecksumDeploySize int64) Option {
+	return func(options *servicesOptions) {
+		options.minChecksumDeploy = &minChecksumDeploySize
+	}
+}
+
+// The number of parts of the files, which are downloaded in parts.
+func WithSplitCount(splitCount int) Option {
+	return func(options *servicesOptions) {
+		options.splitCount = &splitCount
+	}
+}
+
+// The minimum size in KB of the files, which are downloaded in parts.
+func WithMinSplitSize(minSplitSize int64) Option {
+	return func(options *servicesOptions) {
+		options.minSplitSize = &minSplitSize
+	}
+}
+
+// Returns the configuration of the services of the Artifactory server, with the certificates of the security directory.
+// The configuration can be used to create a services manager, as well as the separate services.
+func NewServicesConfig(artDetails *config.ArtifactoryDetails, opts ...Option) (artifactory.Config, error) {
+	options := &servicesOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	certPath, err := GetJfrogSecurityDir()
+	if err != nil {
+		return nil, err
+	}
+	artAuth, err := artDetails.CreateArtAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	configBuilder := artifactory.NewConfigBuilder().
+		SetArtDetails(artAuth).
+		SetCertificatesPath(certPath).
+		SetDryRun(options.dryRun).
+		SetLogger(log.Logger)
+	if options.threads > 0 {
+		configBuilder.SetThreads(options.threads)
+	}
+	if options.minChecksumDeploy != nil {
+		configBuilder.SetMinChecksumDeploy(*options.minChecksumDeploy)
+	}
+	if options.splitCount != nil {
+		configBuilder.SetSplitCount(*options.splitCount)
+	}
+	if options.minSplitSize != nil {
+		configBuilder.SetMinSplitSize(*options.minSplitSize)
+	}
+	return configBuilder.Build()
+}
+
+// Returns a services manager of the Artifactory server. The manager can be reused by multiple commands.
+func NewServicesManager(artDetails *config.ArtifactoryDetails, opts ...Option) (*artifactory.ArtifactoryServicesManager, error) {
+	servicesConfig, err := NewServicesConfig(artDetails, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return artifactory.New(servicesConfig)
+}
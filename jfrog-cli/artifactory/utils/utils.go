@@ -63,24 +63,7 @@ func GetEncryptedPasswordFromArtifactory(artifactoryAuth auth.ArtifactoryDetails
 }
 
 func CreateServiceManager(artDetails *config.ArtifactoryDetails, isDryRun bool) (*artifactory.ArtifactoryServicesManager, error) {
-	certPath, err := GetJfrogSecurityDir()
-	if err != nil {
-		return nil, err
-	}
-	artAuth, err := artDetails.CreateArtAuthConfig()
-	if err != nil {
-		return nil, err
-	}
-	serviceConfig, err := artifactory.NewConfigBuilder().
-		SetArtDetails(artAuth).
-		SetCertificatesPath(certPath).
-		SetDryRun(isDryRun).
-		SetLogger(log.Logger).
-		Build()
-	if err != nil {
-		return nil, err
-	}
-	return artifactory.New(serviceConfig)
+	return NewServicesManager(artDetails, WithDryRun(isDryRun))
 }
 
 func isRepoExists(repository string, artDetails auth.ArtifactoryDetails) (bool, error) {
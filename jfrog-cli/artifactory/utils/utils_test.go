@@ -84,3 +84,30 @@ func TestReturnErrorOnNotFound(t *testing.T) {
 		})
 	}
 }
+
+func TestNewServicesConfig(t *testing.T) {
+	artDetails := &config.ArtifactoryDetails{Url: "http://localhost:8081/artifactory/"}
+	servicesConfig, err := NewServicesConfig(artDetails)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unset options keep the defaults of the client.
+	if servicesConfig.GetThreads() != 3 || servicesConfig.GetMinChecksumDeploy() != 10240 || servicesConfig.IsDryRun() {
+		t.Error("Expected the default settings, got:", servicesConfig.GetThreads(), servicesConfig.GetMinChecksumDeploy(), servicesConfig.IsDryRun())
+	}
+
+	servicesConfig, err = NewServicesConfig(artDetails, WithThreads(8), WithMinChecksumDeploySize(0), WithDryRun(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if servicesConfig.GetThreads() != 8 || servicesConfig.GetMinChecksumDeploy() != 0 || !servicesConfig.IsDryRun() {
+		t.Error("Expected the settings of the options, got:", servicesConfig.GetThreads(), servicesConfig.GetMinChecksumDeploy(), servicesConfig.IsDryRun())
+	}
+	securityDir, err := GetJfrogSecurityDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if servicesConfig.GetCertifactesPath() != securityDir || servicesConfig.GetArtDetails().GetUrl() != artDetails.Url {
+		t.Error("Unexpected certificates path or URL:", servicesConfig.GetCertifactesPath(), servicesConfig.GetArtDetails().GetUrl())
+	}
+}
@@ -0,0 +1,9 @@
+package specvalidate
+
+const Description = "Validate a File Spec."
+
+var Usage = []string{"jfrog rt spec-validate [command options] <File Spec path>"}
+
+const Arguments string = `	File Spec path
+		Path to the File Spec to validate. The File Spec is validated as an upload File Spec, unless the --search-based option is set.
+		Every invalid field is reported with the line in which it was found.`
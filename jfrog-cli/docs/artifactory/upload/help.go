@@ -3,12 +3,16 @@ package upload
 const Description = "Upload files."
 
 var Usage = []string{"jfrog rt u [command options] <source pattern> <target pattern>",
-	"jfrog rt u --spec=<File Spec path> [command options]"}
+	"jfrog rt u --spec=<File Spec path> [command options]",
+	"jfrog rt u --from-stdin [command options] <target path>"}
 
 const Arguments string = `	source pattern
 		Specifies the local file system path to artifacts which should be uploaded to Artifactory.
 		You can specify multiple artifacts by using wildcards or a regular expression as designated by the --regexp command option.
 		If you have specified that you are using regular expressions, then the first one used in the argument must be enclosed in parenthesis.
+		Wildcard patterns may include brace groups, which match any of their comma separated alternatives.
+		For example, "dist/{linux,darwin,windows}/*.tar.gz" matches the archives in the three directories.
+		Use "-" to upload the content read from stdin to the target path.
 
 	target pattern
 		Specifies the target path in Artifactory in the following format: <repository name>/<repository path>.
@@ -17,8 +21,20 @@ const Arguments string = `	source pattern
 		is assumed to be a file to which the uploaded file should be renamed. For example, if you specify the target as "repo-name/a/b",
 		the uploaded file is renamed to "b" in Artifactory.
 		For flexibility in specifying the upload path, you can include placeholders in the form of {1}, {2} which are replaced by corresponding
-		tokens in the source path that are enclosed in parenthesis.`
+		tokens in the source path that are enclosed in parenthesis.
+		The same placeholders can be used in the values of the --props option. When the --regexp option is used, the tokens are
+		the capture groups of the regular expression. Otherwise, they are the parts of the wildcard pattern enclosed in parenthesis.
+		The target path may also include the {year}, {month}, {day} and {epoch} placeholders, which are replaced by the date and time
+		the upload started, so that all of the uploaded files get the same date. For example: "repo-name/builds/{year}/{month}/{day}/".
+		The {sha256}, {sha1} and {md5} placeholders are replaced by the checksums of each of the uploaded files, for a content-addressable
+		layout. For example: "repo-name/blobs/sha256/{sha256}". Files with the same content are uploaded to the same path.`
 
 const EnvVar string = `	JFROG_CLI_MIN_CHECKSUM_DEPLOY_SIZE_KB
 		[Default: 10]
-		Minimum file size in KB for which JFrog CLI performs checksum deploy optimization.`
+		Minimum file size in KB for which JFrog CLI performs checksum deploy optimization.
+		The --min-checksum-deploy command option takes precedence over this variable.
+
+	JFROG_CLI_USER_AGENT_SUFFIX
+		[Optional]
+		A token appended to the User-Agent of the upload requests, so that the uploads can be attributed in the access logs of Artifactory.
+		The --user-agent-suffix command option takes precedence over this variable.`
@@ -0,0 +1,13 @@
+package uploadverify
+
+const Description = "Verify that local files were uploaded, without uploading them."
+
+var Usage = []string{"jfrog rt upload-verify [command options] <source pattern> <target pattern>",
+	"jfrog rt upload-verify --spec=<File Spec path> [command options]"}
+
+const Arguments string = `	source pattern
+		Specifies the local file system path to the files which should be verified, as in the upload command.
+
+	target pattern
+		Specifies the target path in Artifactory, to which the files were uploaded, as in the upload command.
+		Each of the files is reported as MATCH, MISSING or MISMATCH, according to the checksums of its target in Artifactory.`
@@ -0,0 +1,111 @@
+package cliutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The formats of the log.
+const (
+	LogFormatText = "text"
+	LogFormatJson = "json"
+)
+
+// A log message with structured fields, such as the file and the target of an upload.
+// The text log includes only the message, while the JSON log includes the fields in the record.
+type LogRecord struct {
+	Message string
+	Fields  map[string]string
+}
+
+func (record LogRecord) String() string {
+	return record.Message
+}
+
+// A log.Log, which writes each of the log messages as a single line JSON record, with the level, the message, and the
+// fields of the LogRecord arguments. The output of the commands is written as is.
+type JsonLogger struct {
+	logLevel     log.LevelType
+	mutex        sync.Mutex
+	outputWriter io.Writer
+	stderrWriter io.Writer
+}
+
+func NewJsonLogger() *JsonLogger {
+	return &JsonLogger{logLevel: log.GetLogLevel(), outputWriter: os.Stdout, stderrWriter: os.Stderr}
+}
+
+// Returns true if the log messages are written as JSON records, so that structured fields should be logged.
+func IsJsonLog() bool {
+	_, ok := log.Logger.(*JsonLogger)
+	return ok
+}
+
+func (logger *JsonLogger) GetLogLevel() log.LevelType {
+	return logger.logLevel
+}
+
+func (logger *JsonLogger) SetLogLevel(logLevel log.LevelType) {
+	logger.logLevel = logLevel
+}
+
+func (logger *JsonLogger) SetOutputWriter(writer io.Writer) {
+	logger.outputWriter = writer
+}
+
+func (logger *JsonLogger) SetStderrWriter(writer io.Writer) {
+	logger.stderrWriter = writer
+}
+
+func (logger *JsonLogger) Debug(a ...interface{}) {
+	logger.log(log.DEBUG, "debug", a)
+}
+
+func (logger *JsonLogger) Info(a ...interface{}) {
+	logger.log(log.INFO, "info", a)
+}
+
+func (logger *JsonLogger) Warn(a ...interface{}) {
+	logger.log(log.WARN, "warn", a)
+}
+
+func (logger *JsonLogger) Error(a ...interface{}) {
+	logger.log(log.ERROR, "error", a)
+}
+
+func (logger *JsonLogger) Output(a ...interface{}) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	fmt.Fprintln(logger.outputWriter, a...)
+}
+
+func (logger *JsonLogger) log(level log.LevelType, levelName string, a []interface{}) {
+	if logger.logLevel < level {
+		return
+	}
+	record := map[string]string{"time": time.Now().Format(time.RFC3339), "level": levelName}
+	for _, arg := range a {
+		switch value := arg.(type) {
+		case LogRecord:
+			for key, field := range value.Fields {
+				record[key] = field
+			}
+		case error:
+			record["error"] = value.Error()
+		}
+	}
+	record["message"] = strings.TrimSuffix(fmt.Sprintln(a...), "\n")
+	content, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	fmt.Fprintln(logger.stderrWriter, string(content))
+}
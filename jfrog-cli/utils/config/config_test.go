@@ -3,8 +3,14 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCovertConfigV0ToV1(t *testing.T) {
@@ -161,6 +167,83 @@ func TestGetArtifactoriesFromConfig(t *testing.T) {
 	}
 }
 
+func TestCreateArtAuthConfigOidc(t *testing.T) {
+	defer func(wait time.Duration) { oidcRetryWait = wait }(oidcRetryWait)
+	oidcRetryWait = 0
+	attempts := 0
+	var exchangeRequest oidcTokenExchangeRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &exchangeRequest)
+		switch {
+		case exchangeRequest.ProviderName != "github":
+			w.WriteHeader(http.StatusNotFound)
+		case attempts == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"access_token": "short-lived-token"}`))
+		}
+	}))
+	defer ts.Close()
+	tokenFile, err := ioutil.TempFile("", "oidc_token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tokenFile.Name())
+	tokenFile.WriteString("id-token\n")
+	tokenFile.Close()
+
+	details := &ArtifactoryDetails{Url: ts.URL, User: "user", Password: "password", OidcProvider: "github", OidcTokenPath: tokenFile.Name()}
+	artAuth, err := details.CreateArtAuthConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if artAuth.GetAccessToken() != "short-lived-token" || artAuth.GetUser() != "" || artAuth.GetPassword() != "" {
+		t.Error("Expected the exchanged access token to replace the credentials, got:", artAuth.GetAccessToken(), artAuth.GetUser())
+	}
+	if attempts != 2 || exchangeRequest.SubjectToken != "id-token" {
+		t.Errorf("Expected a retried exchange of the ID token, got %d attempts with token %q", attempts, exchangeRequest.SubjectToken)
+	}
+	if details.AccessToken != "" {
+		t.Error("Expected the exchanged access token not to be stored, got:", details.AccessToken)
+	}
+
+	// The exchange is sent through the transport of the details.
+	transport := &countingTransport{transport: http.DefaultTransport}
+	details.OidcTransport = transport
+	if _, err = details.CreateArtAuthConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if transport.requests != 1 {
+		t.Error("Expected the exchange to be sent through the OIDC transport, got requests:", transport.requests)
+	}
+
+	// The OIDC details are not stored in the config.
+	content, err := json.Marshal(details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "oidc") || strings.Contains(string(content), "github") {
+		t.Error("Expected the OIDC details not to be serialized, got:", string(content))
+	}
+
+	details.OidcProvider = "unknown"
+	if _, err = details.CreateArtAuthConfig(); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Error("Expected an error for an unconfigured provider, got:", err)
+	}
+}
+
+type countingTransport struct {
+	transport http.RoundTripper
+	requests  int
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.requests++
+	return ct.transport.RoundTrip(req)
+}
+
 func assertionHelper(configV1 *ConfigV1, t *testing.T) {
 	if configV1.Version != "1" {
 		t.Error(errors.New("Failed to convert config version."))
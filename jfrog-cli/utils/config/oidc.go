@@ -0,0 +1,101 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/httpclient"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	oidcTokenExchangeApi = "access/api/v1/oidc/token"
+	oidcExchangeRetries  = 3
+)
+
+// The time to wait before the first retry of a failed token exchange. Doubled on every retry. Replaced in tests.
+var oidcRetryWait = time.Second
+
+type oidcTokenExchangeRequest struct {
+	GrantType        string `json:"grant_type"`
+	SubjectTokenType string `json:"subject_token_type"`
+	SubjectToken     string `json:"subject_token"`
+	ProviderName     string `json:"provider_name"`
+}
+
+type oidcTokenExchangeResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+// Exchanges the OIDC ID token, read from the token file, for a short-lived Artifactory access token.
+// The exchange is sent through the transport, or through the default transport if it is nil. Transient failures are retried.
+func exchangeOidcToken(artifactoryUrl, providerName, tokenPath string, transport http.RoundTripper) (string, error) {
+	if tokenPath == "" {
+		return "", errorutils.CheckError(errors.New("An OIDC ID token file must be provided for the OIDC provider " + providerName + "."))
+	}
+	idToken, err := ioutil.ReadFile(tokenPath)
+	if errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	content, err := json.Marshal(oidcTokenExchangeRequest{
+		GrantType:        "urn:ietf:params:oauth:grant-type:token-exchange",
+		SubjectTokenType: "urn:ietf:params:oauth:token-type:id_token",
+		SubjectToken:     strings.TrimSpace(string(idToken)),
+		ProviderName:     providerName,
+	})
+	if errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	exchangeUrl := utils.AddTrailingSlashIfNeeded(artifactoryUrl) + oidcTokenExchangeApi
+	httpClientDetails := httputils.HttpClientDetails{Headers: map[string]string{"Content-Type": "application/json"}}
+	client := httpclient.NewDefaultHttpClient()
+	if transport != nil {
+		client = httpclient.NewHttpClient(&http.Client{Transport: transport})
+	}
+	wait := oidcRetryWait
+	for i := 0; ; i++ {
+		resp, body, err := client.SendPost(exchangeUrl, content, httpClientDetails)
+		transient := err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+		if !transient || i == oidcExchangeRetries {
+			if err != nil {
+				return "", err
+			}
+			return parseOidcTokenExchangeResponse(resp, body, providerName)
+		}
+		log.Warn("OIDC token exchange attempt #"+strconv.Itoa(i+1), "failed -", getOidcFailureReason(resp, err)+". Retrying in", wait.String()+"...")
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+func parseOidcTokenExchangeResponse(resp *http.Response, body []byte, providerName string) (string, error) {
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", errorutils.CheckError(errors.New("The OIDC provider " + providerName + " is not configured in Artifactory, or Artifactory does not support OIDC token exchange. Artifactory response: " + resp.Status + "\n" + utils.IndentJson(body)))
+	default:
+		return "", errorutils.CheckError(errors.New("The OIDC token exchange with the provider " + providerName + " failed. Artifactory response: " + resp.Status + "\n" + utils.IndentJson(body)))
+	}
+	exchangeResponse := new(oidcTokenExchangeResponse)
+	if err := json.Unmarshal(body, exchangeResponse); errorutils.CheckError(err) != nil {
+		return "", err
+	}
+	if exchangeResponse.AccessToken == "" {
+		return "", errorutils.CheckError(errors.New("The OIDC token exchange with the provider " + providerName + " returned no access token."))
+	}
+	return exchangeResponse.AccessToken, nil
+}
+
+func getOidcFailureReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}